@@ -0,0 +1,8 @@
+data "pbkdf2_capabilities" "this" {
+  lifecycle {
+    postcondition {
+      condition     = contains(self.format_presets, "postgresql_scram")
+      error_message = "This module needs a pbkdf2 provider version with the postgresql_scram format preset."
+    }
+  }
+}
@@ -0,0 +1,6 @@
+data "pbkdf2_compatibility" "broker" {
+  target      = "mosquitto"
+  prf         = var.prf
+  iterations  = var.iterations
+  salt_length = var.salt_length
+}
@@ -0,0 +1,11 @@
+data "pbkdf2_cost_estimate" "login" {
+  prf        = "hmac-sha256"
+  iterations = 600000
+
+  lifecycle {
+    postcondition {
+      condition     = self.duration_ms < 500
+      error_message = "A login would take ${self.duration_ms}ms, over the 500ms budget."
+    }
+  }
+}
@@ -0,0 +1,11 @@
+provider "pbkdf2" {
+  fingerprint_key = var.fingerprint_key
+}
+
+data "pbkdf2_fingerprint" "example" {
+  password = var.database_password
+}
+
+output "database_password_fingerprint" {
+  value = data.pbkdf2_fingerprint.example.fingerprint
+}
@@ -0,0 +1,8 @@
+resource "pbkdf2_key" "example" {
+  password = var.passphrase
+}
+
+data "pbkdf2_hmac" "example" {
+  key     = pbkdf2_key.example.key
+  message = "bootstrap"
+}
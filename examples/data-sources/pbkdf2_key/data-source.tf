@@ -0,0 +1,5 @@
+data "pbkdf2_key" "example" {
+  password   = var.passphrase
+  salt       = var.salt
+  iterations = 600000
+}
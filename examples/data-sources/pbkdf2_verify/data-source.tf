@@ -0,0 +1,7 @@
+data "pbkdf2_verify" "example" {
+  password = var.password
+  hashes = [
+    var.current_hash,
+    var.previous_hash,
+  ]
+}
@@ -0,0 +1,13 @@
+ephemeral "pbkdf2_key" "example" {
+  password   = var.admin_password
+  iterations = 600000
+}
+
+resource "vault_kv_secret_v2" "example" {
+  mount = "secret"
+  name  = "app/admin"
+  data_json_wo = jsonencode({
+    hash = ephemeral.pbkdf2_key.example.result
+  })
+  data_json_wo_version = 1
+}
@@ -0,0 +1,12 @@
+ephemeral "pbkdf2_salt" "example" {
+  length = 32
+}
+
+resource "vault_kv_secret_v2" "example" {
+  mount = "secret"
+  name  = "app/salt"
+  data_json_wo = jsonencode({
+    salt = ephemeral.pbkdf2_salt.example.hex
+  })
+  data_json_wo_version = 1
+}
@@ -0,0 +1,4 @@
+output "wallet_seed" {
+  value     = provider::pbkdf2::bip39_seed(var.mnemonic, var.passphrase)
+  sensitive = true
+}
@@ -0,0 +1,4 @@
+locals {
+  encryption_key = provider::pbkdf2::hkdf_expand(pbkdf2_key.example.key, "encryption", 32, "sha256")
+  signing_key    = provider::pbkdf2::hkdf_expand(pbkdf2_key.example.key, "signing", 32, "sha256")
+}
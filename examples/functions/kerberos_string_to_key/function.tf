@@ -0,0 +1,3 @@
+locals {
+  http_keytab_key = provider::pbkdf2::kerberos_string_to_key("HTTP/www.example.com", "EXAMPLE.COM", var.service_password, "aes256-cts-hmac-sha1-96")
+}
@@ -0,0 +1,4 @@
+locals {
+  encryption_key = provider::pbkdf2::kmac_derive(pbkdf2_key.example.key, "service-a", "encryption", 32, "kmac256")
+  signing_key    = provider::pbkdf2::kmac_derive(pbkdf2_key.example.key, "service-a", "signing", 32, "kmac256")
+}
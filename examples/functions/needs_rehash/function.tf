@@ -0,0 +1,12 @@
+variable "admin_hash" {
+  type = string
+
+  validation {
+    condition = !provider::pbkdf2::needs_rehash(var.admin_hash, {
+      min_iterations  = 600000
+      hash_algorithm  = "sha256"
+      min_salt_length = 16
+    })
+    error_message = "The admin hash does not meet the current PBKDF2 policy."
+  }
+}
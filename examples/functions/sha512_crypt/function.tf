@@ -0,0 +1,13 @@
+resource "random_password" "salt" {
+  length  = 16
+  special = false
+}
+
+locals {
+  user_data = yamlencode({
+    users = [{
+      name   = "admin"
+      passwd = provider::pbkdf2::sha512_crypt(var.admin_password, random_password.salt.result)
+    }]
+  })
+}
@@ -0,0 +1,10 @@
+resource "terraform_data" "app_role" {
+  input = var.app_role_verifier
+
+  lifecycle {
+    precondition {
+      condition     = provider::pbkdf2::verify_scram(var.app_password, var.app_role_verifier)
+      error_message = "The app role's SCRAM verifier does not match app_password."
+    }
+  }
+}
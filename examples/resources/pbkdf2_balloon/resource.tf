@@ -0,0 +1,5 @@
+resource "pbkdf2_balloon" "example" {
+  password   = var.passphrase
+  space_cost = 65536 # 2 MiB
+  time_cost  = 3
+}
@@ -0,0 +1,4 @@
+# Key and IV for `openssl enc -aes-256-cbc -md md5 -S <salt> -K <key> -iv <iv>`
+resource "pbkdf2_evp_bytes_to_key" "example" {
+  password = var.passphrase
+}
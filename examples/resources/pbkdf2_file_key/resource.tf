@@ -0,0 +1,9 @@
+resource "pbkdf2_file_key" "example" {
+  keyfile    = "${path.module}/backup.key"
+  iterations = 600000
+}
+
+output "backup_encryption_key" {
+  value     = pbkdf2_file_key.example.key
+  sensitive = true
+}
@@ -0,0 +1,15 @@
+resource "pbkdf2_firebase_scrypt" "admin" {
+  password       = var.admin_password
+  signer_key     = var.firebase_signer_key
+  salt_separator = "Bw=="
+  rounds         = 8
+  mem_cost       = 14
+}
+
+output "admin_import_record" {
+  value = {
+    passwordHash = pbkdf2_firebase_scrypt.admin.password_hash
+    salt         = pbkdf2_firebase_scrypt.admin.salt
+  }
+  sensitive = true
+}
@@ -0,0 +1,10 @@
+resource "pbkdf2_keepass_key" "example" {
+  password       = var.vault_password
+  keyfile_base64 = filebase64("${path.module}/vault.keyx")
+  kdf            = "argon2id"
+}
+
+output "vault_transformed_key" {
+  value     = pbkdf2_keepass_key.example.transformed_key
+  sensitive = true
+}
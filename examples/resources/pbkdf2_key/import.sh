@@ -0,0 +1,3 @@
+# Adopt a PBKDF2 hash in PHC or passlib layout, or Django's pbkdf2_sha256$...
+# The first apply records the configured password and keeps the imported salt.
+terraform import pbkdf2_key.example '$pbkdf2-sha256$600000$c2FsdHNhbHRzYWx0c2FsdA$8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg'
@@ -0,0 +1,9 @@
+resource "pbkdf2_luks_key" "example" {
+  passphrase = var.disk_passphrase
+  hash       = "sha256"
+  iterations = 1000000
+}
+
+output "keyslot_kdf" {
+  value = pbkdf2_luks_key.example.luks2_kdf
+}
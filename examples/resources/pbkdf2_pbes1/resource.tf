@@ -0,0 +1,10 @@
+resource "pbkdf2_pbes1" "middleware" {
+  allow_legacy = true
+  scheme       = "pbeWithMD5AndDES-CBC"
+  password     = var.middleware_master_password
+  plaintext    = var.datasource_password
+}
+
+output "encrypted_datasource_password" {
+  value = pbkdf2_pbes1.middleware.ciphertext
+}
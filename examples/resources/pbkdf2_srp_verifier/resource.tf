@@ -0,0 +1,14 @@
+resource "pbkdf2_srp_verifier" "example" {
+  username       = "alice"
+  password       = var.password
+  group          = "3072"
+  hash_algorithm = "sha256"
+}
+
+output "srp" {
+  value = {
+    salt     = pbkdf2_srp_verifier.example.salt
+    verifier = pbkdf2_srp_verifier.example.verifier
+  }
+  sensitive = true
+}
@@ -0,0 +1,9 @@
+resource "pbkdf2_veracrypt_key" "example" {
+  password = var.container_password
+  hash     = "sha512"
+  pim      = 485
+}
+
+output "header_salt" {
+  value = pbkdf2_veracrypt_key.example.salt
+}
@@ -0,0 +1,9 @@
+resource "pbkdf2_web3_keystore" "signer" {
+  private_key = var.signer_private_key
+  password    = var.keystore_password
+}
+
+resource "local_sensitive_file" "keystore" {
+  filename = "${path.module}/keystore/signer.json"
+  content  = pbkdf2_web3_keystore.signer.keystore_json
+}
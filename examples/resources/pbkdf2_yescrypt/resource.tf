@@ -0,0 +1,9 @@
+resource "pbkdf2_yescrypt" "example" {
+  password = var.password
+}
+
+# e.g. for cloud-init's users[].hashed_passwd
+output "hashed_passwd" {
+  value     = pbkdf2_yescrypt.example.result
+  sensitive = true
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// balloonHash computes one hash of the balloon construction over counters and blocks,
+// with integers encoded as 8 byte little endian values.
+func balloonHash(parts ...any) []byte {
+	h := sha256.New()
+	for _, part := range parts {
+		switch v := part.(type) {
+		case int:
+			h.Write(binary.LittleEndian.AppendUint64(nil, uint64(v)))
+		case []byte:
+			h.Write(v)
+		}
+	}
+	return h.Sum(nil)
+}
+
+// balloon implements Balloon hashing (Boneh, Corrigan-Gibbs and Schechter) with SHA-256
+// over spaceCost blocks of 32 bytes, matching the reference Python implementation.
+func balloon(password, salt []byte, spaceCost, timeCost, delta int) []byte {
+	buf := make([][]byte, spaceCost)
+	cnt := 0
+
+	// Expand the input into the buffer.
+	buf[0] = balloonHash(cnt, password, salt)
+	cnt++
+	for m := 1; m < spaceCost; m++ {
+		buf[m] = balloonHash(cnt, buf[m-1])
+		cnt++
+	}
+
+	// Mix the buffer contents.
+	for t := range timeCost {
+		for m := range spaceCost {
+			buf[m] = balloonHash(cnt, buf[(m+spaceCost-1)%spaceCost], buf[m])
+			cnt++
+			for i := range delta {
+				idxBlock := balloonHash(t, m, i)
+				other := balloonHash(cnt, salt, idxBlock)
+				cnt++
+				buf[m] = balloonHash(cnt, buf[m], buf[leModulo(other, spaceCost)])
+				cnt++
+			}
+		}
+	}
+
+	// Extract the output from the buffer.
+	return buf[spaceCost-1]
+}
+
+// leModulo reduces a little endian integer modulo n.
+func leModulo(data []byte, n int) int {
+	var rem uint64
+	for i := len(data) - 1; i >= 0; i-- {
+		rem = (rem<<8 | uint64(data[i])) % uint64(n)
+	}
+	return int(rem)
+}
@@ -0,0 +1,152 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource = &BalloonResource{}
+)
+
+func NewBalloonResource() resource.Resource {
+	return &BalloonResource{}
+}
+
+type BalloonResource struct{}
+
+func (r *BalloonResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_balloon"
+}
+
+func (r *BalloonResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Key derived with Balloon hashing over SHA-256, a memory-hard KDF built only from a standard hash function.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to derive from.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"space_cost": schema.Int64Attribute{
+				MarkdownDescription: "Number of 32 byte blocks in the buffer. Defaults to `16384`, i.e. 512 KiB.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(16384),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1<<25),
+				},
+			},
+			"time_cost": schema.Int64Attribute{
+				MarkdownDescription: "Number of mixing rounds over the buffer. Defaults to `3`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(3),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"delta": schema.Int64Attribute{
+				MarkdownDescription: "Number of pseudorandomly chosen blocks mixed into each block per round. Defaults to `3`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(3),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded salt. Generated from 16 random bytes when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded 32 byte key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type BalloonResourceData struct {
+	Password  types.String `tfsdk:"password"`
+	SpaceCost types.Int64  `tfsdk:"space_cost"`
+	TimeCost  types.Int64  `tfsdk:"time_cost"`
+	Delta     types.Int64  `tfsdk:"delta"`
+	Salt      types.String `tfsdk:"salt"`
+	Key       types.String `tfsdk:"key"`
+}
+
+// deriveBalloon fills in salt and key of data.
+func deriveBalloon(data *BalloonResourceData, diags *diag.Diagnostics) {
+	var salt []byte
+	var err error
+	if data.Salt.IsUnknown() || data.Salt.IsNull() {
+		salt, err = newSalt(16)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(hex.EncodeToString(salt))
+	} else {
+		salt, err = hex.DecodeString(data.Salt.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt"), "Invalid Salt", "The salt is not valid hex.")
+			return
+		}
+	}
+
+	key := balloon([]byte(data.Password.ValueString()), salt,
+		int(data.SpaceCost.ValueInt64()), int(data.TimeCost.ValueInt64()), int(data.Delta.ValueInt64()))
+	data.Key = types.StringValue(hex.EncodeToString(key))
+}
+
+func (r *BalloonResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan BalloonResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveBalloon(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *BalloonResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *BalloonResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state BalloonResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	deriveBalloon(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *BalloonResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
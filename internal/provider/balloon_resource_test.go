@@ -0,0 +1,40 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccBalloonResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_balloon" "test" {
+  password   = "hunter42"
+  salt       = "6578616d706c6573616c74" # examplesalt
+  space_cost = 1024
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_balloon.test", "key", "716043dff777b44aa7b88dcbab12c078abecfac9d289c5b5195967aa63440dfb"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_balloon" "test" {
+  password = "hunter42"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_balloon.test", "salt", "6578616d706c6573616c74"),
+					resource.TestMatchResourceAttr("pbkdf2_balloon.test", "key", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,27 @@
+package provider
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestBalloon(t *testing.T) {
+	cases := []struct {
+		password  string
+		salt      string
+		spaceCost int
+		timeCost  int
+		expected  string
+	}{
+		{"hunter42", "examplesalt", 1024, 3, "716043dff777b44aa7b88dcbab12c078abecfac9d289c5b5195967aa63440dfb"},
+		{"password", "salt", 16, 2, "b7b140d641a5f4b5d1525965a8bac42e5ce2f79ebacad3c24c6e8fe753bb02d7"},
+		{"", "salt", 1, 1, "b500ba63d0eff83d5f162546edbb9d64842db9c86ad8817c8d4bc1d11989a32c"},
+	}
+
+	for _, c := range cases {
+		actual := hex.EncodeToString(balloon([]byte(c.password), []byte(c.salt), c.spaceCost, c.timeCost, 3))
+		if actual != c.expected {
+			t.Errorf("balloon(%q, %q, %d, %d) = %s, want %s", c.password, c.salt, c.spaceCost, c.timeCost, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+// Bench measures the PBKDF2 rate of each named PRF on this machine, or of all of them when names is empty,
+// and writes a table of the iterations taking target per derivation. The recommended count never goes
+// below the interactive security_level.
+func Bench(w io.Writer, target time.Duration, names []string) error {
+	if len(names) == 0 {
+		names = pbkdf2kit.PRFNames()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "PRF\tITERATIONS/S\tITERATIONS (%s)\tRECOMMENDED\n", target)
+	for _, name := range names {
+		p, ok := pbkdf2kit.LookupPRF(name)
+		if !ok {
+			return fmt.Errorf("unknown prf %q, expected one of %s", name, strings.Join(pbkdf2kit.PRFNames(), ", "))
+		}
+		perSecond := calibrateIterations(time.Second, p)
+		forTarget := max(1, int64(float64(perSecond)*target.Seconds()))
+		recommended := max(forTarget, securityLevels["interactive"][p.Name])
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\n", p.Name, perSecond, forTarget, recommended)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,26 @@
+package provider
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+func TestBench(t *testing.T) {
+	var out bytes.Buffer
+	if err := Bench(&out, 10*time.Millisecond, nil); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range pbkdf2kit.PRFNames() {
+		if !strings.Contains(out.String(), name) {
+			t.Errorf("output is missing %s:\n%s", name, out.String())
+		}
+	}
+
+	if err := Bench(&out, 10*time.Millisecond, []string{"md5"}); err == nil {
+		t.Error("expected an error for an unknown prf")
+	}
+}
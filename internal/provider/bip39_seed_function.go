@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha512"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	_ function.Function = &Bip39SeedFunction{}
+)
+
+func NewBip39SeedFunction() function.Function {
+	return &Bip39SeedFunction{}
+}
+
+type Bip39SeedFunction struct{}
+
+func (f *Bip39SeedFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "bip39_seed"
+}
+
+func (f *Bip39SeedFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Computes the BIP39 seed of a mnemonic.",
+		MarkdownDescription: "Derives the 64 byte BIP39 seed from a mnemonic sentence and passphrase (PBKDF2-HMAC-SHA512, 2048 iterations, NFKD normalized) and returns it hex encoded. The mnemonic checksum is not validated.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "mnemonic",
+				MarkdownDescription: "The mnemonic sentence.",
+			},
+			function.StringParameter{
+				Name:                "passphrase",
+				MarkdownDescription: "The optional passphrase; use an empty string for none.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *Bip39SeedFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var mnemonic, passphrase string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &mnemonic, &passphrase))
+	if resp.Error != nil {
+		return
+	}
+
+	seed := pbkdf2.Key(norm.NFKD.Bytes([]byte(mnemonic)), norm.NFKD.Bytes([]byte("mnemonic"+passphrase)), 2048, 64, sha512.New)
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, hex.EncodeToString(seed)))
+}
@@ -0,0 +1,140 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &CapabilitiesDataSource{}
+	_ datasource.DataSourceWithConfigure = &CapabilitiesDataSource{}
+)
+
+// kdfResources are the KDF families the provider can derive with, each backed by its own resource.
+var kdfResources = []string{"pbkdf2", "balloon", "yescrypt", "evp_bytes_to_key", "srp_verifier"}
+
+func NewCapabilitiesDataSource() datasource.DataSource {
+	return &CapabilitiesDataSource{}
+}
+
+type CapabilitiesDataSource struct {
+	provider *pbkdf2ProviderData
+}
+
+func (d *CapabilitiesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_capabilities"
+}
+
+func (d *CapabilitiesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*pbkdf2ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *pbkdf2ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = data
+}
+
+func (d *CapabilitiesDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "What this provider version supports and how it is configured, so reusable modules can check for a feature " +
+			"before using it and fail with a clear message on older versions.",
+
+		Attributes: map[string]schema.Attribute{
+			"provider_version": schema.StringAttribute{
+				MarkdownDescription: "The provider version.",
+				Computed:            true,
+			},
+			"kdfs": schema.ListAttribute{
+				MarkdownDescription: "Supported KDF families, each with a resource of its own: `pbkdf2` for `pbkdf2_key`, `balloon` for `pbkdf2_balloon` and so on.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"prfs": schema.ListAttribute{
+				MarkdownDescription: "Values accepted by `prf` of `pbkdf2_key`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"format_presets": schema.ListAttribute{
+				MarkdownDescription: "Values accepted by `format_preset` of `pbkdf2_key`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"sql_dialects": schema.ListAttribute{
+				MarkdownDescription: "Values accepted by `sql_dialect` of `pbkdf2_key`.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+			"fips_mode": schema.BoolAttribute{
+				MarkdownDescription: "Whether the provider's `fips_mode` is enabled.",
+				Computed:            true,
+			},
+			"min_iterations": schema.Int64Attribute{
+				MarkdownDescription: "The lowest `iterations` of `pbkdf2_key` that is accepted: `1000` in FIPS mode, `1` otherwise.",
+				Computed:            true,
+			},
+			"default_iterations": schema.Int64Attribute{
+				MarkdownDescription: "The `iterations` of `pbkdf2_key` resources that don't set them.",
+				Computed:            true,
+			},
+			"min_password_score": schema.Int64Attribute{
+				MarkdownDescription: "The configured zxcvbn score below which passwords are warned about, `0` when the check is off.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+type CapabilitiesDataSourceData struct {
+	ProviderVersion   types.String `tfsdk:"provider_version"`
+	Kdfs              types.List   `tfsdk:"kdfs"`
+	Prfs              types.List   `tfsdk:"prfs"`
+	FormatPresets     types.List   `tfsdk:"format_presets"`
+	SQLDialects       types.List   `tfsdk:"sql_dialects"`
+	FipsMode          types.Bool   `tfsdk:"fips_mode"`
+	MinIterations     types.Int64  `tfsdk:"min_iterations"`
+	DefaultIterations types.Int64  `tfsdk:"default_iterations"`
+	MinPasswordScore  types.Int64  `tfsdk:"min_password_score"`
+}
+
+func (d *CapabilitiesDataSource) Read(ctx context.Context, _ datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CapabilitiesDataSourceData
+	var diags diag.Diagnostics
+
+	data.Kdfs, diags = types.ListValueFrom(ctx, types.StringType, kdfResources)
+	resp.Diagnostics.Append(diags...)
+	data.Prfs, diags = types.ListValueFrom(ctx, types.StringType, pbkdf2kit.PRFNames())
+	resp.Diagnostics.Append(diags...)
+	data.FormatPresets, diags = types.ListValueFrom(ctx, types.StringType, pbkdf2kit.PresetNames())
+	resp.Diagnostics.Append(diags...)
+	data.SQLDialects, diags = types.ListValueFrom(ctx, types.StringType, sqlDialectNames())
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data.ProviderVersion = types.StringValue("")
+	data.MinPasswordScore = types.Int64Value(0)
+	if d.provider != nil {
+		data.ProviderVersion = types.StringValue(d.provider.Version)
+		data.MinPasswordScore = types.Int64Value(int64(d.provider.MinPasswordScore))
+	}
+	data.FipsMode = types.BoolValue(d.provider.fipsMode())
+	data.MinIterations = types.Int64Value(d.provider.minIterations())
+	data.DefaultIterations = types.Int64Value(d.provider.defaultIterations())
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCapabilitiesDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  fips_mode          = true
+  default_iterations = 600000
+}
+
+data "pbkdf2_capabilities" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_capabilities.test", "provider_version", "test"),
+					resource.TestCheckTypeSetElemAttr("data.pbkdf2_capabilities.test", "prfs.*", "hmac-sha512"),
+					resource.TestCheckTypeSetElemAttr("data.pbkdf2_capabilities.test", "format_presets.*", "mosquitto"),
+					resource.TestCheckTypeSetElemAttr("data.pbkdf2_capabilities.test", "kdfs.*", "yescrypt"),
+					resource.TestCheckResourceAttr("data.pbkdf2_capabilities.test", "fips_mode", "true"),
+					resource.TestCheckResourceAttr("data.pbkdf2_capabilities.test", "min_iterations", "1000"),
+					resource.TestCheckResourceAttr("data.pbkdf2_capabilities.test", "default_iterations", "600000"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource = &CompatibilityDataSource{}
+)
+
+func NewCompatibilityDataSource() datasource.DataSource {
+	return &CompatibilityDataSource{}
+}
+
+type CompatibilityDataSource struct{}
+
+func (d *CompatibilityDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_compatibility"
+}
+
+func (d *CompatibilityDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Checks a PBKDF2 parameter set, or an existing hash, against the constraints of the consumer behind a `format_preset` of `pbkdf2_key`, " +
+			"such as a required PRF or an exact salt length, and fails the plan listing every violation.",
+
+		Attributes: map[string]schema.Attribute{
+			"target": schema.StringAttribute{
+				MarkdownDescription: "The format preset whose consumer to check against: " + markdownList(pbkdf2kit.PresetNames()) + ".",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PresetNames()...),
+				},
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "The pseudorandom function.",
+				Optional:            true,
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of iterations.",
+				Optional:            true,
+			},
+			"salt_length": schema.Int64Attribute{
+				MarkdownDescription: "Salt length in bytes.",
+				Optional:            true,
+			},
+			"key_length": schema.Int64Attribute{
+				MarkdownDescription: "Key length in bytes.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"hash": schema.StringAttribute{
+				MarkdownDescription: "An existing hash to take the parameter set from instead, in a PHC, passlib or Django layout as understood by `needs_rehash`.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(
+						path.MatchRoot("prf"),
+						path.MatchRoot("iterations"),
+						path.MatchRoot("salt_length"),
+						path.MatchRoot("key_length"),
+					),
+				},
+			},
+			"fail_on_violation": schema.BoolAttribute{
+				MarkdownDescription: "Fail the read when there are violations. Set to `false` to only report them in `violations`, e.g. for `check` blocks. Defaults to `true`.",
+				Optional:            true,
+			},
+			"violations": schema.ListAttribute{
+				MarkdownDescription: "The violated constraints, empty when the parameters are compatible.",
+				ElementType:         types.StringType,
+				Computed:            true,
+			},
+		},
+	}
+}
+
+type CompatibilityDataSourceData struct {
+	Target          types.String `tfsdk:"target"`
+	Prf             types.String `tfsdk:"prf"`
+	Iterations      types.Int64  `tfsdk:"iterations"`
+	SaltLength      types.Int64  `tfsdk:"salt_length"`
+	KeyLength       types.Int64  `tfsdk:"key_length"`
+	Hash            types.String `tfsdk:"hash"`
+	FailOnViolation types.Bool   `tfsdk:"fail_on_violation"`
+	Violations      types.List   `tfsdk:"violations"`
+}
+
+func (d *CompatibilityDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CompatibilityDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	preset, _ := pbkdf2kit.LookupPreset(data.Target.ValueString())
+	params := pbkdf2kit.ParameterSet{
+		PRF:        data.Prf.ValueString(),
+		Iterations: data.Iterations.ValueInt64(),
+		SaltLength: int(data.SaltLength.ValueInt64()),
+		KeyLength:  int(data.KeyLength.ValueInt64()),
+	}
+	if !data.Hash.IsNull() {
+		parsed, err := pbkdf2kit.ParseHash(data.Hash.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("hash"), "Invalid Hash", "The hash can't be parsed: "+err.Error()+".")
+			return
+		}
+		params = pbkdf2kit.ParameterSet{
+			PRF:        "hmac-" + parsed.HashAlgorithm,
+			Iterations: int64(parsed.Iterations),
+			SaltLength: len(parsed.Salt),
+			KeyLength:  len(parsed.Key),
+		}
+	}
+
+	violations := preset.Violations(params)
+	if len(violations) > 0 && (data.FailOnViolation.IsNull() || data.FailOnViolation.ValueBool()) {
+		resp.Diagnostics.AddError("Incompatible Parameters",
+			"The "+preset.Name+" format preset:\n  - "+strings.Join(violations, "\n  - "))
+		return
+	}
+
+	if violations == nil {
+		violations = []string{}
+	}
+	violationsValue, diags := types.ListValueFrom(ctx, types.StringType, violations)
+	resp.Diagnostics.Append(diags...)
+	data.Violations = violationsValue
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCompatibilityDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "pbkdf2_compatibility" "test" {
+  target      = "mosquitto"
+  prf         = "hmac-sha256"
+  iterations  = 101
+  salt_length = 16
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)requires prf = "hmac-sha512", got "hmac-sha256".*requires a 12 byte salt, got 16 bytes`),
+			},
+			{
+				Config: `
+data "pbkdf2_compatibility" "test" {
+  target            = "mosquitto"
+  prf               = "hmac-sha256"
+  fail_on_violation = false
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_compatibility.test", "violations.#", "1"),
+				),
+			},
+			{
+				Config: `
+data "pbkdf2_compatibility" "test" {
+  target = "postgresql_scram"
+  hash   = "$pbkdf2-sha256$4096$MDEyMzQ1Njc4OWFiY2RlZg$oluz8P.sdMSh4alFZx5yVgplUgXYUGY.Fi89jyycHYo"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_compatibility.test", "violations.#", "0"),
+				),
+			},
+		},
+	})
+}
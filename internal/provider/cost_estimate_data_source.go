@@ -0,0 +1,189 @@
+package provider
+
+import (
+	"context"
+	"time"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource = &CostEstimateDataSource{}
+)
+
+func NewCostEstimateDataSource() datasource.DataSource {
+	return &CostEstimateDataSource{}
+}
+
+type CostEstimateDataSource struct{}
+
+func (d *CostEstimateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_cost_estimate"
+}
+
+func (d *CostEstimateDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Projected cost of a single derivation with a parameter set, measured on the machine running Terraform. " +
+			"A scaled down derivation is timed and extrapolated, so large parameters are estimated without being run. " +
+			"Use it in postconditions to keep the login path of the target system within its latency budget, " +
+			"allowing for the speed difference between this machine and the target.",
+
+		Attributes: map[string]schema.Attribute{
+			"algorithm": schema.StringAttribute{
+				MarkdownDescription: "The KDF to estimate: `pbkdf2`, `balloon` or `yescrypt`, with the parameters of `pbkdf2_key`, `pbkdf2_balloon` and `pbkdf2_yescrypt` respectively. Defaults to `pbkdf2`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("pbkdf2", "balloon", "yescrypt"),
+				},
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "PBKDF2 pseudorandom function: " + markdownList(pbkdf2kit.PRFNames()) + ". Defaults to `" + pbkdf2kit.DefaultPRF + "`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PRFNames()...),
+				},
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "PBKDF2 iterations. Defaults to `100000`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"space_cost": schema.Int64Attribute{
+				MarkdownDescription: "Balloon buffer size in 32 byte blocks. Defaults to `16384`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1<<25),
+				},
+			},
+			"time_cost": schema.Int64Attribute{
+				MarkdownDescription: "Balloon mixing rounds. Defaults to `3`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"delta": schema.Int64Attribute{
+				MarkdownDescription: "Balloon blocks mixed into each block per round. Defaults to `3`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"n_log2": schema.Int64Attribute{
+				MarkdownDescription: "yescrypt base 2 logarithm of the block count. Defaults to `12`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(2, 20),
+				},
+			},
+			"r": schema.Int64Attribute{
+				MarkdownDescription: "yescrypt block size. Defaults to `32`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 64),
+				},
+			},
+			"duration_ms": schema.Float64Attribute{
+				MarkdownDescription: "Projected wall clock time of one derivation in milliseconds.",
+				Computed:            true,
+			},
+			"memory_bytes": schema.Int64Attribute{
+				MarkdownDescription: "Size of the buffer held during one derivation. Null for `pbkdf2`, which needs no more than a few hash states.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+type CostEstimateDataSourceData struct {
+	Algorithm   types.String  `tfsdk:"algorithm"`
+	Prf         types.String  `tfsdk:"prf"`
+	Iterations  types.Int64   `tfsdk:"iterations"`
+	SpaceCost   types.Int64   `tfsdk:"space_cost"`
+	TimeCost    types.Int64   `tfsdk:"time_cost"`
+	Delta       types.Int64   `tfsdk:"delta"`
+	NLog2       types.Int64   `tfsdk:"n_log2"`
+	R           types.Int64   `tfsdk:"r"`
+	DurationMs  types.Float64 `tfsdk:"duration_ms"`
+	MemoryBytes types.Int64   `tfsdk:"memory_bytes"`
+}
+
+// Scaled down derivations are capped at these sizes, which run in milliseconds.
+const (
+	probeIterations = 1000
+	probeSpaceCost  = 4096
+	probeNLog2      = 10
+)
+
+// timeRun repeats run until at least 20ms have passed and returns the mean duration of one run.
+func timeRun(run func()) time.Duration {
+	var elapsed time.Duration
+	runs := 0
+	for elapsed < 20*time.Millisecond {
+		start := time.Now()
+		run()
+		elapsed += time.Since(start)
+		runs++
+	}
+	return elapsed / time.Duration(runs)
+}
+
+// int64Or returns the value of v, or fallback when it is null.
+func int64Or(v types.Int64, fallback int64) int64 {
+	if v.IsNull() {
+		return fallback
+	}
+	return v.ValueInt64()
+}
+
+func (d *CostEstimateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data CostEstimateDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	password := []byte("cost estimate")
+	salt := make([]byte, 16)
+	var perRun time.Duration
+	var scale float64
+	data.MemoryBytes = types.Int64Null()
+
+	switch data.Algorithm.ValueString() {
+	case "balloon":
+		spaceCost := int64Or(data.SpaceCost, 16384)
+		timeCost, delta := int(int64Or(data.TimeCost, 3)), int(int64Or(data.Delta, 3))
+		// Every phase of Balloon is linear in the buffer size.
+		probe := min(spaceCost, probeSpaceCost)
+		perRun = timeRun(func() { balloon(password, salt, int(probe), timeCost, delta) })
+		scale = float64(spaceCost) / float64(probe)
+		data.MemoryBytes = types.Int64Value(spaceCost * 32)
+	case "yescrypt":
+		nLog2, r := int64Or(data.NLog2, 12), int(int64Or(data.R, 32))
+		// yescrypt's work is linear in N for a fixed r.
+		probe := min(nLog2, probeNLog2)
+		perRun = timeRun(func() { yescryptKey(password, salt, int(probe), r) })
+		scale = float64(int64(1) << (nLog2 - probe))
+		data.MemoryBytes = types.Int64Value(128 * int64(r) << nLog2)
+	default:
+		name := pbkdf2kit.DefaultPRF
+		if !data.Prf.IsNull() {
+			name = data.Prf.ValueString()
+		}
+		p := lookupPRF(name)
+		iterations := int64Or(data.Iterations, 100000)
+		perRun = timeRun(func() { _, _ = pbkdf2kit.Key(p, password, salt, probeIterations, p.Size) })
+		scale = float64(iterations) / probeIterations
+	}
+
+	data.DurationMs = types.Float64Value(float64(perRun) * scale / float64(time.Millisecond))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
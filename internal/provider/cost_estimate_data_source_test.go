@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccCostEstimateDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+data "pbkdf2_cost_estimate" "pbkdf2" {
+  iterations = 600000
+}
+
+data "pbkdf2_cost_estimate" "balloon" {
+  algorithm  = "balloon"
+  space_cost = 65536
+}
+
+data "pbkdf2_cost_estimate" "yescrypt" {
+  algorithm = "yescrypt"
+  n_log2    = 16
+  r         = 8
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("data.pbkdf2_cost_estimate.pbkdf2", "duration_ms", regexp.MustCompile(`^[0-9.e+]+$`)),
+					resource.TestCheckNoResourceAttr("data.pbkdf2_cost_estimate.pbkdf2", "memory_bytes"),
+					resource.TestCheckResourceAttr("data.pbkdf2_cost_estimate.balloon", "memory_bytes", "2097152"),
+					resource.TestCheckResourceAttr("data.pbkdf2_cost_estimate.yescrypt", "memory_bytes", "67108864"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,65 @@
+package provider
+
+import (
+	"crypto/sha256"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// seenMaterial remembers the salts and keys handled by one provider instance, so a salt or key shared
+// between resources of the same plan or apply can be reported. Only digests are kept.
+type seenMaterial struct {
+	mu     sync.Mutex
+	values map[[sha256.Size]byte]struct{}
+}
+
+func newSeenMaterial() *seenMaterial {
+	return &seenMaterial{values: map[[sha256.Size]byte]struct{}{}}
+}
+
+// record remembers value as material of the given kind and reports whether it was recorded before.
+func (s *seenMaterial) record(kind, value string) bool {
+	digest := sha256.Sum256([]byte(kind + "\x00" + value))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[digest]; ok {
+		return true
+	}
+	s.values[digest] = struct{}{}
+	return false
+}
+
+// checkDuplicates records the salts and keys of one pbkdf2_key and warns about those another key already had.
+func checkDuplicates(seen *seenMaterial, diags *diag.Diagnostics, salt, key, nextSalt, nextKey types.String) {
+	if seen == nil {
+		return
+	}
+
+	for _, material := range []struct {
+		attr  string
+		kind  string
+		value types.String
+	}{
+		{"salt", "salt", salt},
+		{"next_salt", "salt", nextSalt},
+		{"key", "key", key},
+		{"next_key", "key", nextKey},
+	} {
+		if material.value.IsNull() || material.value.IsUnknown() || !seen.record(material.kind, material.value.ValueString()) {
+			continue
+		}
+		if material.kind == "salt" {
+			diags.AddAttributeWarning(path.Root(material.attr), "Duplicate Salt",
+				"Another pbkdf2_key in this run has the same salt, so equal passwords derive equal keys and a single "+
+					"guessing attack covers both. This usually means two keys share a salt_seed; give each key its own.")
+		} else {
+			diags.AddAttributeWarning(path.Root(material.attr), "Duplicate Key",
+				"Another pbkdf2_key in this run derived the same key, so both hash the same password with the same salt. "+
+					"Anyone holding one of them holds the other.")
+		}
+	}
+}
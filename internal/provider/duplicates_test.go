@@ -0,0 +1,36 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestCheckDuplicates(t *testing.T) {
+	seen := newSeenMaterial()
+
+	var diags diag.Diagnostics
+	checkDuplicates(seen, &diags, types.StringValue("salt-1"), types.StringValue("key-1"), types.StringNull(), types.StringNull())
+	if len(diags) != 0 {
+		t.Fatalf("unexpected diagnostics for the first key: %v", diags)
+	}
+
+	checkDuplicates(seen, &diags, types.StringValue("salt-2"), types.StringValue("salt-1"), types.StringNull(), types.StringNull())
+	if len(diags) != 0 {
+		t.Fatalf("a key equal to another key's salt must not count as a duplicate: %v", diags)
+	}
+
+	checkDuplicates(seen, &diags, types.StringValue("salt-3"), types.StringValue("key-3"), types.StringValue("salt-1"), types.StringValue("key-1"))
+	if len(diags) != 2 || diags[0].Summary() != "Duplicate Salt" || diags[1].Summary() != "Duplicate Key" {
+		t.Fatalf("expected a duplicate salt and key warning, got %v", diags)
+	}
+	if diags.HasError() {
+		t.Error("duplicates must only warn")
+	}
+
+	checkDuplicates(nil, &diags, types.StringValue("salt-1"), types.StringNull(), types.StringNull(), types.StringNull())
+	if len(diags) != 2 {
+		t.Errorf("warned without tracking: %v", diags)
+	}
+}
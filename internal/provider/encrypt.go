@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"filippo.io/age"
+	"filippo.io/age/agessh"
+	"filippo.io/age/armor"
+)
+
+// parseRecipient parses an age X25519 recipient (age1...) or an SSH public key (ssh-ed25519, ssh-rsa).
+func parseRecipient(recipient string) (age.Recipient, error) {
+	recipient = strings.TrimSpace(recipient)
+	if strings.HasPrefix(recipient, "age1") {
+		return age.ParseX25519Recipient(recipient)
+	}
+	return agessh.ParseRecipient(recipient)
+}
+
+// parseRecipients parses every recipient with parseRecipient.
+func parseRecipients(recipients []string) ([]age.Recipient, error) {
+	parsed := make([]age.Recipient, 0, len(recipients))
+	for i, recipient := range recipients {
+		r, err := parseRecipient(recipient)
+		if err != nil {
+			return nil, fmt.Errorf("recipient %d: %w", i, err)
+		}
+		parsed = append(parsed, r)
+	}
+	return parsed, nil
+}
+
+// encryptFor encrypts plaintext to all recipients as an ASCII armored age file.
+func encryptFor(recipients []age.Recipient, plaintext []byte) (string, error) {
+	var out bytes.Buffer
+	armored := armor.NewWriter(&out)
+	w, err := age.Encrypt(armored, recipients...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.Write(plaintext); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if err := armored.Close(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+)
+
+func TestEncryptFor(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	recipients, err := parseRecipients([]string{
+		identity.Recipient().String(),
+		"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIHsKLqeplhpW+uObz5dvMgjz1OxfM/XXUB+VHtZ6isGN",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := encryptFor(recipients, []byte("derived key"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(ciphertext, armor.Header) {
+		t.Errorf("ciphertext is not armored: %q", ciphertext)
+	}
+
+	r, err := age.Decrypt(armor.NewReader(strings.NewReader(ciphertext)), identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	plaintext, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(plaintext) != "derived key" {
+		t.Errorf("got %q, want %q", plaintext, "derived key")
+	}
+}
+
+func TestParseRecipients_invalid(t *testing.T) {
+	for _, recipient := range []string{"age1invalid", "ssh-dss AAAA", ""} {
+		if _, err := parseRecipients([]string{recipient}); err == nil {
+			t.Errorf("%q: expected an error", recipient)
+		}
+	}
+}
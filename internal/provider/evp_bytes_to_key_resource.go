@@ -0,0 +1,222 @@
+package provider
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource = &EvpBytesToKeyResource{}
+)
+
+// evpDigests are the message digests accepted by OpenSSL's `enc -md` for EVP_BytesToKey.
+var evpDigests = map[string]func() hash.Hash{
+	"md5":    md5.New,
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+}
+
+func NewEvpBytesToKeyResource() resource.Resource {
+	return &EvpBytesToKeyResource{}
+}
+
+type EvpBytesToKeyResource struct{}
+
+func (r *EvpBytesToKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_evp_bytes_to_key"
+}
+
+func (r *EvpBytesToKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Legacy OpenSSL `EVP_BytesToKey` key and IV derivation, as used by `openssl enc` without `-pbkdf2`. " +
+			"**Insecure**: a single round of a fast digest offers next to no protection against guessing. " +
+			"Only use it to interoperate with old tooling; use `pbkdf2_key` otherwise.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to derive from.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The 8 byte salt, hex encoded as printed by `openssl enc -P`. Generated when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-fA-F]{16}$`), "must be 8 hex encoded bytes"),
+					stringvalidator.ConflictsWith(path.MatchRoot("no_salt")),
+				},
+			},
+			"no_salt": schema.BoolAttribute{
+				MarkdownDescription: "Derive without a salt, like `openssl enc -nosalt`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"hash_algorithm": schema.StringAttribute{
+				MarkdownDescription: "The message digest: `md5`, `sha1` or `sha256`. Defaults to `md5`, the OpenSSL default before 1.1.0.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("md5"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("md5", "sha1", "sha256"),
+				},
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of times each digest is applied. `openssl enc` always uses `1`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"key_length": schema.Int64Attribute{
+				MarkdownDescription: "Length of the cipher key in bytes. Defaults to `32`, as for AES-256.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(32),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"iv_length": schema.Int64Attribute{
+				MarkdownDescription: "Length of the IV in bytes. Defaults to `16`, as for AES in CBC mode.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(16),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded cipher key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"iv": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded IV.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type EvpBytesToKeyResourceData struct {
+	Password      types.String `tfsdk:"password"`
+	Salt          types.String `tfsdk:"salt"`
+	NoSalt        types.Bool   `tfsdk:"no_salt"`
+	HashAlgorithm types.String `tfsdk:"hash_algorithm"`
+	Iterations    types.Int64  `tfsdk:"iterations"`
+	KeyLength     types.Int64  `tfsdk:"key_length"`
+	IvLength      types.Int64  `tfsdk:"iv_length"`
+	Key           types.String `tfsdk:"key"`
+	Iv            types.String `tfsdk:"iv"`
+}
+
+// evpBytesToKey implements OpenSSL's EVP_BytesToKey: D_i = H^count(D_(i-1) || password || salt),
+// concatenated until there is enough material for the key followed by the IV.
+func evpBytesToKey(hashFunc func() hash.Hash, password, salt []byte, count, keyLen, ivLen int) ([]byte, []byte) {
+	var material, block []byte
+	for len(material) < keyLen+ivLen {
+		h := hashFunc()
+		h.Write(block)
+		h.Write(password)
+		h.Write(salt)
+		block = h.Sum(nil)
+		for i := 1; i < count; i++ {
+			h.Reset()
+			h.Write(block)
+			block = h.Sum(nil)
+		}
+		material = append(material, block...)
+	}
+	return material[:keyLen], material[keyLen : keyLen+ivLen]
+}
+
+// deriveEvp fills in salt, key and iv of data.
+func deriveEvp(data *EvpBytesToKeyResourceData, diags *diag.Diagnostics) {
+	var salt []byte
+	switch {
+	case data.NoSalt.ValueBool():
+		data.Salt = types.StringNull()
+	case data.Salt.IsUnknown() || data.Salt.IsNull():
+		var err error
+		salt, err = newSalt(8)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(hex.EncodeToString(salt))
+	default:
+		var err error
+		salt, err = hex.DecodeString(data.Salt.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt"), "Invalid Salt", "The salt is not valid hex.")
+			return
+		}
+	}
+
+	key, iv := evpBytesToKey(evpDigests[data.HashAlgorithm.ValueString()], []byte(data.Password.ValueString()), salt,
+		int(data.Iterations.ValueInt64()), int(data.KeyLength.ValueInt64()), int(data.IvLength.ValueInt64()))
+	data.Key = types.StringValue(hex.EncodeToString(key))
+	data.Iv = types.StringValue(hex.EncodeToString(iv))
+}
+
+func (r *EvpBytesToKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan EvpBytesToKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveEvp(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *EvpBytesToKeyResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *EvpBytesToKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state EvpBytesToKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() && !plan.NoSalt.ValueBool() && !state.Salt.IsNull() {
+		plan.Salt = state.Salt
+	}
+
+	deriveEvp(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *EvpBytesToKeyResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
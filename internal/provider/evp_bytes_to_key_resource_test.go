@@ -0,0 +1,41 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccEvpBytesToKeyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// openssl enc -aes-256-cbc -k password -S 0001020304050607 -md md5 -P
+				Config: `
+resource "pbkdf2_evp_bytes_to_key" "test" {
+  password = "password"
+  salt     = "0001020304050607"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_evp_bytes_to_key.test", "key", "b03096345e805d3aa4392d2e72791dfb13e12d3f61094a3fc347ace86b99ada6"),
+					resource.TestCheckResourceAttr("pbkdf2_evp_bytes_to_key.test", "iv", "acde38b46073eef81840283e44a4b22a"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_evp_bytes_to_key" "test" {
+  password = "password"
+  no_salt  = true
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pbkdf2_evp_bytes_to_key.test", "salt"),
+					resource.TestCheckResourceAttr("pbkdf2_evp_bytes_to_key.test", "key", "5f4dcc3b5aa765d61d8327deb882cf992b95990a9151374abd8ff8c5a7a0fe08"),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,269 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource               = &FileKeyResource{}
+	_ resource.ResourceWithModifyPlan = &FileKeyResource{}
+)
+
+func NewFileKeyResource() resource.Resource {
+	return &FileKeyResource{}
+}
+
+type FileKeyResource struct{}
+
+func (r *FileKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_file_key"
+}
+
+func (r *FileKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "PBKDF2 key derived from the contents of a keyfile instead of a text password, for keyfile based encryption workflows, " +
+			"optionally combined with a password as in two-factor key unlock schemes. The keyfile is read during plan, so a changed file re-derives the key.",
+
+		Attributes: map[string]schema.Attribute{
+			"keyfile": schema.StringAttribute{
+				MarkdownDescription: "Path of the keyfile. Exactly one of `keyfile` and `keyfile_base64` must be set.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("keyfile"), path.MatchRoot("keyfile_base64")),
+				},
+			},
+			"keyfile_base64": schema.StringAttribute{
+				MarkdownDescription: "The keyfile contents, base64 encoded, e.g. from `filebase64()` or a secret store.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "Password combined with the keyfile into a composite secret as selected by `composite_mode`, so both are needed to derive the key.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"composite_mode": schema.StringAttribute{
+				MarkdownDescription: "How `password` and the keyfile are combined before derivation: `hash` for `SHA-256(password || keyfile)`, " +
+					"`hmac` for `HMAC-SHA256(keyfile, password)` keyed with the keyfile. Defaults to `hash` when `password` is set.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(compositeModes...),
+					stringvalidator.AlsoRequires(path.MatchRoot("password")),
+				},
+			},
+			"keyfile_sha256": schema.StringAttribute{
+				MarkdownDescription: "Hex encoded SHA-256 of the keyfile contents, to tell which keyfile the key was derived from.",
+				Computed:            true,
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "The pseudorandom function to use: " + markdownList(pbkdf2kit.PRFNames()) + ". Defaults to `" + pbkdf2kit.DefaultPRF + "`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(pbkdf2kit.DefaultPRF),
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PRFNames()...),
+				},
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of iterations. Defaults to `100000`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(100000),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"key_length": schema.Int64Attribute{
+				MarkdownDescription: "Length of the key in bytes. Defaults to the output size of `prf`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1024),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded salt. Generated from 16 random bytes when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type FileKeyResourceData struct {
+	Keyfile       types.String `tfsdk:"keyfile"`
+	KeyfileBase64 types.String `tfsdk:"keyfile_base64"`
+	Password      types.String `tfsdk:"password"`
+	CompositeMode types.String `tfsdk:"composite_mode"`
+	KeyfileSha256 types.String `tfsdk:"keyfile_sha256"`
+	Prf           types.String `tfsdk:"prf"`
+	Iterations    types.Int64  `tfsdk:"iterations"`
+	KeyLength     types.Int64  `tfsdk:"key_length"`
+	Salt          types.String `tfsdk:"salt"`
+	Key           types.String `tfsdk:"key"`
+}
+
+// compositeModes are the values of composite_mode.
+var compositeModes = []string{"hash", "hmac"}
+
+// compositeSecret combines password and keyfile into the PBKDF2 input, or returns the keyfile alone without a password.
+func compositeSecret(data FileKeyResourceData, keyfile []byte) []byte {
+	if data.Password.IsNull() {
+		return keyfile
+	}
+	if data.CompositeMode.ValueString() == "hmac" {
+		mac := hmac.New(sha256.New, keyfile)
+		mac.Write([]byte(data.Password.ValueString()))
+		return mac.Sum(nil)
+	}
+	digest := sha256.Sum256(append([]byte(data.Password.ValueString()), keyfile...))
+	return digest[:]
+}
+
+// readKeyfile returns the keyfile contents of data, reporting errors against the attribute they came from.
+func readKeyfile(data FileKeyResourceData, diags *diag.Diagnostics) []byte {
+	if !data.Keyfile.IsNull() {
+		contents, err := os.ReadFile(data.Keyfile.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("keyfile"), "Invalid Keyfile", err.Error())
+		}
+		return contents
+	}
+	contents, err := base64.StdEncoding.DecodeString(data.KeyfileBase64.ValueString())
+	if err != nil {
+		// The contents are secret, so they are not quoted.
+		diags.AddAttributeError(path.Root("keyfile_base64"), "Invalid Keyfile", "The keyfile is not valid base64.")
+	}
+	return contents
+}
+
+// deriveFileKey fills in keyfile_sha256, salt and key of data.
+func deriveFileKey(data *FileKeyResourceData, diags *diag.Diagnostics) {
+	keyfile := readKeyfile(*data, diags)
+	if diags.HasError() {
+		return
+	}
+	digest := sha256.Sum256(keyfile)
+	data.KeyfileSha256 = types.StringValue(hex.EncodeToString(digest[:]))
+
+	var salt []byte
+	var err error
+	if data.Salt.IsUnknown() || data.Salt.IsNull() {
+		salt, err = newSalt(16)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(base64.StdEncoding.EncodeToString(salt))
+	} else {
+		salt, err = base64.StdEncoding.DecodeString(data.Salt.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt"), "Invalid Salt", "The salt is not base64 encoded: "+err.Error()+".")
+			return
+		}
+	}
+
+	p := lookupPRF(data.Prf.ValueString())
+	keyLen := p.Size
+	if !data.KeyLength.IsNull() {
+		keyLen = int(data.KeyLength.ValueInt64())
+	}
+	dk, err := pbkdf2kit.Key(p, compositeSecret(*data, keyfile), salt, int(data.Iterations.ValueInt64()), keyLen)
+	if err != nil {
+		diags.AddAttributeError(path.Root("prf"), "Derivation Error", err.Error())
+		return
+	}
+	data.Key = types.StringValue(base64.StdEncoding.EncodeToString(dk))
+}
+
+// ModifyPlan reads the keyfile so a change of its contents shows up in the plan and re-derives the key.
+func (r *FileKeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan FileKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Keyfile.IsUnknown() || plan.KeyfileBase64.IsUnknown() {
+		return
+	}
+
+	keyfile := readKeyfile(plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	digest := sha256.Sum256(keyfile)
+	plan.KeyfileSha256 = types.StringValue(hex.EncodeToString(digest[:]))
+
+	if !req.State.Raw.IsNull() {
+		var state FileKeyResourceData
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if !plan.KeyfileSha256.Equal(state.KeyfileSha256) {
+			plan.Key = types.StringUnknown()
+		}
+	}
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r *FileKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan FileKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveFileKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *FileKeyResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *FileKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state FileKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	deriveFileKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *FileKeyResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccFileKeyResource(t *testing.T) {
+	keyfile := filepath.Join(t.TempDir(), "keyfile")
+	if err := os.WriteFile(keyfile, []byte("keyfile contents"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config := fmt.Sprintf(`
+resource "pbkdf2_file_key" "test" {
+  keyfile    = %q
+  iterations = 1000
+  salt       = "c2FsdHNhbHRzYWx0c2FsdA=="
+}
+`, keyfile)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_file_key.test", "key", "kR1x5YCeUvFDGZzrfrJvaP7kFJxduF5lyoNCr0WNtlQ="),
+					resource.TestCheckResourceAttr("pbkdf2_file_key.test", "keyfile_sha256", "f5679615f17a83e430333fb68772617d571d5034d2fff000dfbcf738475703a1"),
+				),
+			},
+			{
+				// A changed keyfile re-derives the key without a change to the configuration.
+				PreConfig: func() {
+					if err := os.WriteFile(keyfile, []byte("other contents"), 0o600); err != nil {
+						t.Fatal(err)
+					}
+				},
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_file_key.test", "key", "xgrPsMhOM1emsLJIbR+ebH2wlnI1h6gTW+9LfSWl1PQ="),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_file_key" "test" {
+  keyfile_base64 = "a2V5ZmlsZSBjb250ZW50cw=="
+  iterations     = 1000
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The salt is kept when it is no longer configured.
+					resource.TestCheckResourceAttr("pbkdf2_file_key.test", "salt", "c2FsdHNhbHRzYWx0c2FsdA=="),
+					resource.TestCheckResourceAttr("pbkdf2_file_key.test", "key", "kR1x5YCeUvFDGZzrfrJvaP7kFJxduF5lyoNCr0WNtlQ="),
+				),
+			},
+		},
+	})
+}
+
+func TestAccFileKeyResource_composite(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_file_key" "hash" {
+  keyfile_base64 = "a2V5ZmlsZSBjb250ZW50cw=="
+  password       = "hunter2"
+  iterations     = 1000
+  salt           = "c2FsdHNhbHRzYWx0c2FsdA=="
+}
+
+resource "pbkdf2_file_key" "hmac" {
+  keyfile_base64 = "a2V5ZmlsZSBjb250ZW50cw=="
+  password       = "hunter2"
+  composite_mode = "hmac"
+  iterations     = 1000
+  salt           = "c2FsdHNhbHRzYWx0c2FsdA=="
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_file_key.hash", "key", "e3G7vesSYJHtYhFpY3eI98+IhNCwn2wDoDOcWlAAIRU="),
+					resource.TestCheckResourceAttr("pbkdf2_file_key.hmac", "key", "n5Yjj1k3SCfoGffr2yKxxoDGMPrsQNGHhh1up2cGGow="),
+				),
+			},
+		},
+	})
+}
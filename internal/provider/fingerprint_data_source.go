@@ -0,0 +1,92 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &FingerprintDataSource{}
+	_ datasource.DataSourceWithConfigure = &FingerprintDataSource{}
+)
+
+func NewFingerprintDataSource() datasource.DataSource {
+	return &FingerprintDataSource{}
+}
+
+type FingerprintDataSource struct {
+	provider *pbkdf2ProviderData
+}
+
+func (d *FingerprintDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_fingerprint"
+}
+
+func (d *FingerprintDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*pbkdf2ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *pbkdf2ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = data
+}
+
+func (d *FingerprintDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Keyed fingerprint of a password, `HMAC-SHA256(fingerprint_key, password)` with the provider's `fingerprint_key`. " +
+			"It changes whenever the password does and is equal for equal passwords across workspaces sharing the key, " +
+			"but can't be reversed or guessed against without the key.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to fingerprint.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"fingerprint": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded fingerprint.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+type FingerprintDataSourceData struct {
+	Password    types.String `tfsdk:"password"`
+	Fingerprint types.String `tfsdk:"fingerprint"`
+}
+
+func (d *FingerprintDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data FingerprintDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if d.provider == nil || d.provider.FingerprintKey == "" {
+		resp.Diagnostics.AddError("Missing Fingerprint Key",
+			"pbkdf2_fingerprint needs fingerprint_key in the provider configuration. Without a secret key "+
+				"a fingerprint would be a plain hash that can be cracked like any unsalted password hash.")
+		return
+	}
+
+	mac := hmac.New(sha256.New, []byte(d.provider.FingerprintKey))
+	mac.Write([]byte(data.Password.ValueString()))
+	data.Fingerprint = types.StringValue(hex.EncodeToString(mac.Sum(nil)))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
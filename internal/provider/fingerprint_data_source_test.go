@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccFingerprintDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  fingerprint_key = "fp-key"
+}
+
+data "pbkdf2_fingerprint" "test" {
+  password = "example"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_fingerprint.test", "fingerprint", "3bf7a12d8873169e29e68b89ca0cd4f2560ab8555ad6cd9dbdaf56568f473c95"),
+				),
+			},
+			{
+				Config: `
+data "pbkdf2_fingerprint" "test" {
+  password = "example"
+}
+`,
+				ExpectError: regexp.MustCompile(`Missing Fingerprint Key`),
+			},
+		},
+	})
+}
@@ -0,0 +1,191 @@
+package provider
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/base64"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	_ resource.Resource = &FirebaseScryptResource{}
+)
+
+func NewFirebaseScryptResource() resource.Resource {
+	return &FirebaseScryptResource{}
+}
+
+type FirebaseScryptResource struct{}
+
+func (r *FirebaseScryptResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_firebase_scrypt"
+}
+
+func (r *FirebaseScryptResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Password hash in Firebase Authentication's modified scrypt, the project's signer key encrypted with AES-256-CTR under the scrypt key of the password, " +
+			"to pre-seed users with `firebase auth:import --hash-algo=SCRYPT` or re-hash exported users during migrations. " +
+			"To validate an exported user, set `salt` to its salt and compare `password_hash` with its hash.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The user's password.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"signer_key": schema.StringAttribute{
+				MarkdownDescription: "The project's `base64_signer_key` from the password hash parameters in the Firebase console.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"salt_separator": schema.StringAttribute{
+				MarkdownDescription: "The project's `base64_salt_separator`. Defaults to `Bw==`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("Bw=="),
+			},
+			"rounds": schema.Int64Attribute{
+				MarkdownDescription: "The project's `rounds`, the scrypt block size. Defaults to `8`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(8),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 8),
+				},
+			},
+			"mem_cost": schema.Int64Attribute{
+				MarkdownDescription: "The project's `mem_cost`, the base 2 logarithm of the scrypt cost. Defaults to `14`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(14),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 14),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded salt of the user. Generated from 16 random bytes when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"password_hash": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded password hash.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type FirebaseScryptResourceData struct {
+	Password      types.String `tfsdk:"password"`
+	SignerKey     types.String `tfsdk:"signer_key"`
+	SaltSeparator types.String `tfsdk:"salt_separator"`
+	Rounds        types.Int64  `tfsdk:"rounds"`
+	MemCost       types.Int64  `tfsdk:"mem_cost"`
+	Salt          types.String `tfsdk:"salt"`
+	PasswordHash  types.String `tfsdk:"password_hash"`
+}
+
+// firebaseScrypt hashes password as Firebase does: the signer key encrypted with AES-256-CTR and a zero IV,
+// keyed with scrypt over password and salt followed by the salt separator.
+func firebaseScrypt(password, salt, saltSeparator, signerKey []byte, rounds, memCost int) ([]byte, error) {
+	key, err := scrypt.Key(password, append(append([]byte(nil), salt...), saltSeparator...), 1<<memCost, rounds, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	hash := make([]byte, len(signerKey))
+	cipher.NewCTR(block, make([]byte, aes.BlockSize)).XORKeyStream(hash, signerKey)
+	return hash, nil
+}
+
+// decodeBase64 decodes a base64 attribute, reporting an error against it unless it is valid.
+func decodeBase64(value types.String, attr string, diags *diag.Diagnostics) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(value.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root(attr), "Invalid "+attr, "Expected a base64 encoded value.")
+	}
+	return decoded
+}
+
+// deriveFirebaseScrypt fills in salt and password_hash of data.
+func deriveFirebaseScrypt(data *FirebaseScryptResourceData, diags *diag.Diagnostics) {
+	var salt []byte
+	if data.Salt.IsUnknown() || data.Salt.IsNull() {
+		var err error
+		salt, err = newSalt(16)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(base64.StdEncoding.EncodeToString(salt))
+	} else {
+		salt = decodeBase64(data.Salt, "salt", diags)
+	}
+	signerKey := decodeBase64(data.SignerKey, "signer_key", diags)
+	saltSeparator := decodeBase64(data.SaltSeparator, "salt_separator", diags)
+	if diags.HasError() {
+		return
+	}
+
+	hash, err := firebaseScrypt([]byte(data.Password.ValueString()), salt, saltSeparator, signerKey,
+		int(data.Rounds.ValueInt64()), int(data.MemCost.ValueInt64()))
+	if err != nil {
+		diags.AddError("Derivation Error", err.Error())
+		return
+	}
+	data.PasswordHash = types.StringValue(base64.StdEncoding.EncodeToString(hash))
+}
+
+func (r *FirebaseScryptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan FirebaseScryptResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveFirebaseScrypt(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *FirebaseScryptResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *FirebaseScryptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state FirebaseScryptResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	deriveFirebaseScrypt(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *FirebaseScryptResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// The sample parameters of github.com/firebase/scrypt.
+const (
+	firebaseTestSignerKey = "jxspr8Ki0RYycVU8zykbdLGjFQ3McFUH0uiiTvC8pVMXAn210wjLNmdZJzxUECKbm0QsEmYUSDzZvpjeJ9WmXA=="
+	firebaseTestSalt      = "42xEC+ixf3L2lw=="
+	firebaseTestHash      = "lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ=="
+)
+
+func TestFirebaseScrypt(t *testing.T) {
+	signerKey, _ := base64.StdEncoding.DecodeString(firebaseTestSignerKey)
+	salt, _ := base64.StdEncoding.DecodeString(firebaseTestSalt)
+	hash, err := firebaseScrypt([]byte("user1password"), salt, []byte{0x07}, signerKey, 8, 14)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := base64.StdEncoding.EncodeToString(hash); actual != firebaseTestHash {
+		t.Errorf("got %s", actual)
+	}
+}
+
+func TestAccFirebaseScryptResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_firebase_scrypt" "test" {
+  password   = "user1password"
+  signer_key = "` + firebaseTestSignerKey + `"
+  salt       = "` + firebaseTestSalt + `"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_firebase_scrypt.test", "password_hash", firebaseTestHash),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_firebase_scrypt" "test" {
+  password   = "user1password"
+  signer_key = "` + firebaseTestSignerKey + `"
+  mem_cost   = 10
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The salt is kept when it is no longer configured.
+					resource.TestCheckResourceAttr("pbkdf2_firebase_scrypt.test", "salt", firebaseTestSalt),
+				),
+			},
+		},
+	})
+}
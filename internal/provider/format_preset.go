@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+// formatPresetsMarkdown describes every preset as a markdown list item.
+func formatPresetsMarkdown() string {
+	var out strings.Builder
+	for _, preset := range pbkdf2kit.Presets {
+		out.WriteString("\n  - `" + preset.Name + "`: " + preset.Description)
+		var requires []string
+		if preset.PRF != "" {
+			requires = append(requires, "`prf = \""+preset.PRF+"\"`")
+		}
+		if len(preset.PRFs) > 0 {
+			requires = append(requires, "`prf` of "+orList(preset.PRFs))
+		}
+		if preset.TextSalt {
+			requires = append(requires, "`salt_charset` of "+orList(saltCharsetNames[1:]))
+		}
+		if preset.SaltLength != 0 {
+			requires = append(requires, "`salt_length = "+strconv.Itoa(preset.SaltLength)+"`")
+		}
+		if preset.DigestKeyLength {
+			requires = append(requires, "the default `key_length`")
+		}
+		if len(requires) > 0 {
+			out.WriteString(" (requires " + strings.Join(requires, " and ") + ")")
+		}
+		out.WriteString(".")
+	}
+	return out.String()
+}
+
+// orList quotes names as a markdown enumeration ending in "or".
+func orList(names []string) string {
+	quoted := markdownList(names)
+	if i := strings.LastIndex(quoted, ", "); i >= 0 {
+		quoted = quoted[:i] + " or " + quoted[i+2:]
+	}
+	return quoted
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"golang.org/x/crypto/hkdf"
+)
+
+var (
+	_ function.Function = &HkdfExpandFunction{}
+)
+
+func NewHkdfExpandFunction() function.Function {
+	return &HkdfExpandFunction{}
+}
+
+type HkdfExpandFunction struct{}
+
+func (f *HkdfExpandFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "hkdf_expand"
+}
+
+func (f *HkdfExpandFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Expands a derived key into a subkey with HKDF.",
+		MarkdownDescription: "Runs the HKDF-Expand step (RFC 5869) over an existing derived key, so several independent subkeys can be drawn from one `pbkdf2_key` by varying `info`. Returns the subkey base64 encoded.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "key",
+				MarkdownDescription: "The base64 encoded derived key, as exposed by the `key` attribute of `pbkdf2_key`.",
+			},
+			function.StringParameter{
+				Name:                "info",
+				MarkdownDescription: "Context and application specific information binding the subkey to its purpose.",
+			},
+			function.Int64Parameter{
+				Name:                "length",
+				MarkdownDescription: "Length of the subkey in bytes.",
+			},
+			function.StringParameter{
+				Name:                "hash",
+				MarkdownDescription: "The hash function to use: " + markdownList(prfAliases()) + ".",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *HkdfExpandFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var key, info, hashAlgorithm string
+	var length int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &key, &info, &length, &hashAlgorithm))
+	if resp.Error != nil {
+		return
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "key must be base64 encoded, as the key attribute of pbkdf2_key is")
+		return
+	}
+	if !slices.Contains(prfAliases(), hashAlgorithm) {
+		resp.Error = function.NewArgumentFuncError(3, "hash must be one of "+strings.Join(prfAliases(), ", "))
+		return
+	}
+	size, hashFunc := getHashAlgorithm(hashAlgorithm)
+	if length < 1 || length > int64(255*size) {
+		resp.Error = function.NewArgumentFuncError(2, "length must be between 1 and 255 times the hash output size")
+		return
+	}
+
+	subkey := make([]byte, length)
+	if _, err := io.ReadFull(hkdf.Expand(hashFunc, secret, []byte(info)), subkey); err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, base64.StdEncoding.EncodeToString(subkey)))
+}
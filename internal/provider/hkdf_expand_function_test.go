@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccHkdfExpandFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::hkdf_expand("a2V5", "info", 32, "sha256")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "Wh5Hl6DRRb1uWJBNgP5DmTdJgSnj6alSLXMDe5JEpAE="),
+				),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::hkdf_expand("a2V5", "info", 0, "sha256")
+}
+`,
+				ExpectError: regexp.MustCompile(`length must be between 1 and 255`),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::hkdf_expand("a2V5", "info", 32, "sha-256")
+}
+`,
+				ExpectError: regexp.MustCompile(`hash must be one of`),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::hkdf_expand("key", "info", 32, "sha256")
+}
+`,
+				ExpectError: regexp.MustCompile(`key must be base64 encoded`),
+			},
+		},
+	})
+}
@@ -0,0 +1,109 @@
+package provider
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource = &HmacDataSource{}
+)
+
+func NewHmacDataSource() datasource.DataSource {
+	return &HmacDataSource{}
+}
+
+type HmacDataSource struct{}
+
+func (d *HmacDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_hmac"
+}
+
+func (d *HmacDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "HMAC of a message keyed with a PBKDF2 derived key.",
+
+		Attributes: map[string]schema.Attribute{
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded derived key, as exposed by the `key` attribute of `pbkdf2_key`.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"message": schema.StringAttribute{
+				MarkdownDescription: "The message to authenticate.",
+				Required:            true,
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "The HMAC to use: " + markdownList(hmacPRFNames()) + ". Defaults to `" + pbkdf2kit.DefaultPRF + "`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(hmacPRFNames()...),
+					stringvalidator.ConflictsWith(path.MatchRoot("hash_algorithm")),
+				},
+			},
+			"hash_algorithm": schema.StringAttribute{
+				MarkdownDescription: "The hash function to use, as the bare hash name of `prf`: " + markdownList(prfAliases()) + ".",
+				DeprecationMessage:  "Use prf instead, e.g. hmac-sha256 instead of sha256.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(prfAliases()...),
+				},
+			},
+			"hex": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded HMAC.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"base64": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded HMAC.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type HmacDataSourceData struct {
+	Key           types.String `tfsdk:"key"`
+	Message       types.String `tfsdk:"message"`
+	Prf           types.String `tfsdk:"prf"`
+	HashAlgorithm types.String `tfsdk:"hash_algorithm"`
+	Hex           types.String `tfsdk:"hex"`
+	Base64        types.String `tfsdk:"base64"`
+}
+
+func (d *HmacDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data HmacDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	name := data.Prf.ValueString()
+	if data.Prf.IsNull() {
+		name = data.HashAlgorithm.ValueString()
+	}
+	_, hashFunc := getHashAlgorithm(name)
+	key := decodeBase64(data.Key, "key", &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	mac := hmac.New(hashFunc, key)
+	mac.Write([]byte(data.Message.ValueString()))
+	sum := mac.Sum(nil)
+
+	data.Hex = types.StringValue(hex.EncodeToString(sum))
+	data.Base64 = types.StringValue(base64.StdEncoding.EncodeToString(sum))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccHmacDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccHmacDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_hmac.test", "hex", "f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"),
+					resource.TestCheckResourceAttr("data.pbkdf2_hmac.test", "base64", "97yD9DBThCSxMpjmqm+xQ+9NWaFJRhdZl0edvC0aPNg="),
+				),
+			},
+			{
+				Config: `
+data "pbkdf2_hmac" "test" {
+  key     = "a2V5"
+  message = "The quick brown fox jumps over the lazy dog"
+  prf     = "hmac-sha512"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_hmac.test", "hex", "b42af09057bac1e2d41708e48a902e09b5ff7f12ab428a4fe86653c73dd248fb82f948a549f7b791a5b41915ee4d1ec3935357e4e2317250d0372afa2ebeeb3a"),
+				),
+			},
+			{
+				// The key is the base64 encoded key of pbkdf2_key, not raw text.
+				Config: `
+data "pbkdf2_hmac" "test" {
+  key     = "key"
+  message = "The quick brown fox jumps over the lazy dog"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid key`),
+			},
+		},
+	})
+}
+
+// The key is "key" base64 encoded, so the HMACs are the well-known ones of that key.
+const testAccHmacDataSourceConfig = `
+data "pbkdf2_hmac" "test" {
+  key     = "a2V5"
+  message = "The quick brown fox jumps over the lazy dog"
+}
+`
@@ -0,0 +1,20 @@
+package provider
+
+import (
+	"crypto/aes"
+	"encoding/hex"
+	"strings"
+)
+
+// keyCheckValue computes the AES key check value of key: the first 3 bytes of the encryption
+// of an all zero block, in the upper case hex HSMs display. It reports false for lengths that
+// are no AES key size.
+func keyCheckValue(key []byte) (string, bool) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", false
+	}
+	out := make([]byte, aes.BlockSize)
+	block.Encrypt(out, make([]byte, aes.BlockSize))
+	return strings.ToUpper(hex.EncodeToString(out[:3])), true
+}
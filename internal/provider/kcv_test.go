@@ -0,0 +1,20 @@
+package provider
+
+import "testing"
+
+func TestKeyCheckValue(t *testing.T) {
+	cases := map[int]string{
+		16: "66E94B",
+		32: "DC95C0",
+	}
+	for length, expected := range cases {
+		actual, ok := keyCheckValue(make([]byte, length))
+		if !ok || actual != expected {
+			t.Errorf("%d byte key: got %q, want %q", length, actual, expected)
+		}
+	}
+
+	if _, ok := keyCheckValue(make([]byte, 20)); ok {
+		t.Error("expected no check value for a 20 byte key")
+	}
+}
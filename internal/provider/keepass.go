@@ -0,0 +1,88 @@
+package provider
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// keepassKeyfile is the XML keyfile format of KeePass 2, version 1.0 with base64 and 2.0 with hex data.
+type keepassKeyfile struct {
+	XMLName xml.Name `xml:"KeyFile"`
+	Version string   `xml:"Meta>Version"`
+	Data    string   `xml:"Key>Data"`
+}
+
+// keepassKeyfileData turns a keyfile into its part of the composite key the way KeePass does: the key of an
+// XML keyfile, 32 raw bytes, 64 hex digits, and the SHA-256 of any other file.
+func keepassKeyfileData(file []byte) ([]byte, error) {
+	var parsed keepassKeyfile
+	if bytes.HasPrefix(bytes.TrimSpace(file), []byte("<")) && xml.Unmarshal(file, &parsed) == nil {
+		switch {
+		case strings.HasPrefix(parsed.Version, "1."):
+			return base64.StdEncoding.DecodeString(strings.TrimSpace(parsed.Data))
+		case strings.HasPrefix(parsed.Version, "2."):
+			return hex.DecodeString(strings.Join(strings.Fields(parsed.Data), ""))
+		default:
+			return nil, fmt.Errorf("unsupported XML keyfile version %q", parsed.Version)
+		}
+	}
+	if len(file) == 32 {
+		return file, nil
+	}
+	if len(file) == 64 {
+		if data, err := hex.DecodeString(string(file)); err == nil {
+			return data, nil
+		}
+	}
+	digest := sha256.Sum256(file)
+	return digest[:], nil
+}
+
+// keepassCompositeKey is the SHA-256 of the SHA-256 of the password followed by the keyfile data,
+// leaving out whichever of the two is nil.
+func keepassCompositeKey(password, keyfileData []byte) []byte {
+	h := sha256.New()
+	if password != nil {
+		digest := sha256.Sum256(password)
+		h.Write(digest[:])
+	}
+	h.Write(keyfileData)
+	return h.Sum(nil)
+}
+
+// keepassAESKDF transforms the composite key by encrypting both of its halves rounds times with AES-256
+// keyed with seed, and hashing the result with SHA-256.
+func keepassAESKDF(compositeKey, seed []byte, rounds uint64) ([]byte, error) {
+	block, err := aes.NewCipher(seed)
+	if err != nil {
+		return nil, err
+	}
+	key := append([]byte(nil), compositeKey...)
+	for i := uint64(0); i < rounds; i++ {
+		block.Encrypt(key[:16], key[:16])
+		block.Encrypt(key[16:], key[16:])
+	}
+	digest := sha256.Sum256(key)
+	return digest[:], nil
+}
+
+// keepassArgon2id transforms the composite key with Argon2id version 0x13, memory given in KiB.
+func keepassArgon2id(compositeKey, salt []byte, iterations, memory uint32, parallelism uint8) []byte {
+	return argon2.IDKey(compositeKey, salt, iterations, memory, parallelism, 32)
+}
+
+// keepassMasterKeys derives the KDBX 4 cipher key and HMAC base key of a database from its master seed.
+func keepassMasterKeys(masterSeed, transformedKey []byte) ([]byte, []byte) {
+	cipherKey := sha256.Sum256(append(append([]byte(nil), masterSeed...), transformedKey...))
+	hmacKey := sha512.Sum512(append(append(append([]byte(nil), masterSeed...), transformedKey...), 0x01))
+	return cipherKey[:], hmacKey[:]
+}
@@ -0,0 +1,247 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource = &KeepassKeyResource{}
+)
+
+func NewKeepassKeyResource() resource.Resource {
+	return &KeepassKeyResource{}
+}
+
+type KeepassKeyResource struct{}
+
+func (r *KeepassKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_keepass_key"
+}
+
+func (r *KeepassKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "KeePass KDBX master key material: the composite key of a password and keyfile, transformed with AES-KDF or Argon2id as KeePass does, " +
+			"so shared vaults can be provisioned with keys computed by Terraform. Write `seed` and the KDF parameters to the KDF parameters of the database header. " +
+			"Argon2d, the KeePass default, is not supported.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The master password. At least one of `password` and `keyfile_base64` must be set.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.AtLeastOneOf(path.MatchRoot("password"), path.MatchRoot("keyfile_base64")),
+				},
+			},
+			"keyfile_base64": schema.StringAttribute{
+				MarkdownDescription: "The key file, base64 encoded, e.g. from `filebase64()`. XML key files, 32 byte binary and 64 digit hex key files are used as KeePass reads them, any other file by its SHA-256.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"kdf": schema.StringAttribute{
+				MarkdownDescription: "The key derivation function: `aes` for AES-KDF or `argon2id`. Defaults to `argon2id`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("argon2id"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("aes", "argon2id"),
+				},
+			},
+			"aes_rounds": schema.Int64Attribute{
+				MarkdownDescription: "AES-KDF transformation rounds. Defaults to `60000`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(60000),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"argon2_iterations": schema.Int64Attribute{
+				MarkdownDescription: "Argon2id iterations. Defaults to `2`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(2),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1<<32-1),
+				},
+			},
+			"argon2_memory_kib": schema.Int64Attribute{
+				MarkdownDescription: "Argon2id memory in KiB. The KDBX header stores it in bytes. Defaults to `65536`, 64 MiB.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(65536),
+				Validators: []validator.Int64{
+					int64validator.Between(8, 1<<32-1),
+				},
+			},
+			"argon2_parallelism": schema.Int64Attribute{
+				MarkdownDescription: "Argon2id parallelism. Defaults to `2`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(2),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 255),
+				},
+			},
+			"seed": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded 32 byte AES-KDF transform seed or Argon2id salt. Generated randomly when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"master_seed": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded 32 byte master seed of a KDBX 4 database, to compute `cipher_key` and `hmac_key` of that database.",
+				Optional:            true,
+			},
+			"transformed_key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded transformed key, the output of the KDF over the composite key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"cipher_key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded key of the database cipher, `SHA-256(master_seed || transformed_key)`. Null unless `master_seed` is set.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"hmac_key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded KDBX 4 HMAC base key, `SHA-512(master_seed || transformed_key || 0x01)`. Null unless `master_seed` is set.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type KeepassKeyResourceData struct {
+	Password          types.String `tfsdk:"password"`
+	KeyfileBase64     types.String `tfsdk:"keyfile_base64"`
+	Kdf               types.String `tfsdk:"kdf"`
+	AesRounds         types.Int64  `tfsdk:"aes_rounds"`
+	Argon2Iterations  types.Int64  `tfsdk:"argon2_iterations"`
+	Argon2MemoryKib   types.Int64  `tfsdk:"argon2_memory_kib"`
+	Argon2Parallelism types.Int64  `tfsdk:"argon2_parallelism"`
+	Seed              types.String `tfsdk:"seed"`
+	MasterSeed        types.String `tfsdk:"master_seed"`
+	TransformedKey    types.String `tfsdk:"transformed_key"`
+	CipherKey         types.String `tfsdk:"cipher_key"`
+	HmacKey           types.String `tfsdk:"hmac_key"`
+}
+
+// decodeFixed decodes a base64 attribute that must hold exactly size bytes.
+func decodeFixed(value types.String, attr string, size int, diags *diag.Diagnostics) []byte {
+	decoded, err := base64.StdEncoding.DecodeString(value.ValueString())
+	if err != nil || len(decoded) != size {
+		diags.AddAttributeError(path.Root(attr), "Invalid "+attr, fmt.Sprintf("Expected %d base64 encoded bytes.", size))
+	}
+	return decoded
+}
+
+// transformKeepassKey fills in seed and the derived keys of data.
+func transformKeepassKey(data *KeepassKeyResourceData, diags *diag.Diagnostics) {
+	var password, keyfileData []byte
+	if !data.Password.IsNull() {
+		password = []byte(data.Password.ValueString())
+	}
+	if !data.KeyfileBase64.IsNull() {
+		keyfile, err := base64.StdEncoding.DecodeString(data.KeyfileBase64.ValueString())
+		if err != nil {
+			// The key file is secret, so it is not quoted.
+			diags.AddAttributeError(path.Root("keyfile_base64"), "Invalid Keyfile", "The key file is not valid base64.")
+			return
+		}
+		if keyfileData, err = keepassKeyfileData(keyfile); err != nil {
+			diags.AddAttributeError(path.Root("keyfile_base64"), "Invalid Keyfile", err.Error())
+			return
+		}
+	}
+	compositeKey := keepassCompositeKey(password, keyfileData)
+
+	var seed []byte
+	if data.Seed.IsUnknown() || data.Seed.IsNull() {
+		var err error
+		seed, err = newSalt(32)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Seed = types.StringValue(base64.StdEncoding.EncodeToString(seed))
+	} else if seed = decodeFixed(data.Seed, "seed", 32, diags); diags.HasError() {
+		return
+	}
+
+	var transformed []byte
+	if data.Kdf.ValueString() == "aes" {
+		var err error
+		if transformed, err = keepassAESKDF(compositeKey, seed, uint64(data.AesRounds.ValueInt64())); err != nil {
+			diags.AddError("Derivation Error", err.Error())
+			return
+		}
+	} else {
+		transformed = keepassArgon2id(compositeKey, seed, uint32(data.Argon2Iterations.ValueInt64()),
+			uint32(data.Argon2MemoryKib.ValueInt64()), uint8(data.Argon2Parallelism.ValueInt64()))
+	}
+	data.TransformedKey = types.StringValue(base64.StdEncoding.EncodeToString(transformed))
+
+	data.CipherKey = types.StringNull()
+	data.HmacKey = types.StringNull()
+	if !data.MasterSeed.IsNull() {
+		masterSeed := decodeFixed(data.MasterSeed, "master_seed", 32, diags)
+		if diags.HasError() {
+			return
+		}
+		cipherKey, hmacKey := keepassMasterKeys(masterSeed, transformed)
+		data.CipherKey = types.StringValue(base64.StdEncoding.EncodeToString(cipherKey))
+		data.HmacKey = types.StringValue(base64.StdEncoding.EncodeToString(hmacKey))
+	}
+}
+
+func (r *KeepassKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan KeepassKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	transformKeepassKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *KeepassKeyResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *KeepassKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state KeepassKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Seed.IsUnknown() {
+		plan.Seed = state.Seed
+	}
+	transformKeepassKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *KeepassKeyResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
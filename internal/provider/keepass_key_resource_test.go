@@ -0,0 +1,75 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccKeepassKeyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_keepass_key" "test" {
+  password       = "password"
+  keyfile_base64 = "bm90IGEga2VlcGFzcyBrZXlmaWxl"
+  kdf            = "aes"
+  aes_rounds     = 1000
+  seed           = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+  master_seed    = "ICEiIyQlJicoKSorLC0uLzAxMjM0NTY3ODk6Ozw9Pj8="
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_keepass_key.test", "transformed_key", "ZzpRJNR5KakxfGAY/K+ZjXuwSUZD0XqLqlxeN1zq5tg="),
+					resource.TestCheckResourceAttr("pbkdf2_keepass_key.test", "cipher_key", "0GZ6M1KlZFarxVIT4Nh4Dk76dp2wkXFJe5fUM9zYIa0="),
+					resource.TestCheckResourceAttr("pbkdf2_keepass_key.test", "hmac_key", "jtvW1YdTMNctSKq6G29Ykcz91JsQYoIhZHCtPRyYnlEYKAIokw0nui6I5sfRgbk0r86CwUJ93YYDfuF87lpcrA=="),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_keepass_key" "test" {
+  password          = "password"
+  argon2_memory_kib = 1024
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The seed is kept when it is no longer configured.
+					resource.TestCheckResourceAttr("pbkdf2_keepass_key.test", "seed", "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="),
+					resource.TestCheckResourceAttr("pbkdf2_keepass_key.test", "kdf", "argon2id"),
+					resource.TestCheckResourceAttrSet("pbkdf2_keepass_key.test", "transformed_key"),
+					resource.TestCheckNoResourceAttr("pbkdf2_keepass_key.test", "cipher_key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeepassKeyResource_invalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_keepass_key" "test" {
+  kdf = "aes"
+}
+`,
+				ExpectError: regexp.MustCompile(`password|keyfile_base64`),
+			},
+			{
+				Config: `
+resource "pbkdf2_keepass_key" "test" {
+  password = "password"
+  seed     = "c2hvcnQ="
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid seed`),
+			},
+		},
+	})
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestKeepassKeyfileData(t *testing.T) {
+	key := bytes.Repeat([]byte{0xab}, 32)
+	cases := map[string]string{
+		"xml 1.0": `<?xml version="1.0" encoding="utf-8"?>
+<KeyFile><Meta><Version>1.00</Version></Meta><Key><Data>q6urq6urq6urq6urq6urq6urq6urq6urq6urq6urq6s=</Data></Key></KeyFile>`,
+		"xml 2.0": `<?xml version="1.0" encoding="utf-8"?>
+<KeyFile><Meta><Version>2.0</Version></Meta><Key><Data Hash="00000000">
+  ABABABAB ABABABAB ABABABAB ABABABAB
+  ABABABAB ABABABAB ABABABAB ABABABAB
+</Data></Key></KeyFile>`,
+		"binary": string(key),
+		"hex":    strings.Repeat("ab", 32),
+	}
+	for name, file := range cases {
+		data, err := keepassKeyfileData([]byte(file))
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+		} else if !bytes.Equal(data, key) {
+			t.Errorf("%s: got %x", name, data)
+		}
+	}
+
+	data, err := keepassKeyfileData([]byte("not a keepass keyfile"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := hex.EncodeToString(data); actual != "0308b98c3133dda244c1a7fa4c052c135b88217b31dbb689cd129f7f7fdd1cd0" {
+		t.Errorf("other files must be hashed, got %s", actual)
+	}
+}
+
+func TestKeepassAESKDF(t *testing.T) {
+	keyfileData, _ := keepassKeyfileData([]byte("not a keepass keyfile"))
+	compositeKey := keepassCompositeKey([]byte("password"), keyfileData)
+	if actual := hex.EncodeToString(compositeKey); actual != "fac6914c5ef8c0d9d0a3b5a77927a31dff8d962d0c64e9ac7fc8476d71f443d9" {
+		t.Errorf("composite key: got %s", actual)
+	}
+
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	transformed, err := keepassAESKDF(compositeKey, seed, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := hex.EncodeToString(transformed); actual != "673a5124d47929a9317c6018fcaf998d7bb0494643d17a8baa5c5e375ceae6d8" {
+		t.Errorf("transformed key: got %s", actual)
+	}
+
+	masterSeed := make([]byte, 32)
+	for i := range masterSeed {
+		masterSeed[i] = byte(32 + i)
+	}
+	cipherKey, hmacKey := keepassMasterKeys(masterSeed, transformed)
+	if actual := hex.EncodeToString(cipherKey); actual != "d0667a3352a56456abc55213e0d8780e4efa769db09171497b97d433dcd821ad" {
+		t.Errorf("cipher key: got %s", actual)
+	}
+	if actual := hex.EncodeToString(hmacKey); actual != "8edbd6d5875330d72d48aaba1b6f5891ccfdd49b106282216470ad3d1c989e51"+
+		"18280228930d27ba2e88e6c7d181b934afce82c1427ddd86037ee17cee5a5cac" {
+		t.Errorf("hmac key: got %s", actual)
+	}
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"crypto/aes"
+	"crypto/sha1"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// kerberosIterations is the RFC 3962 default iteration count, used when the KDC sends no s2kparams.
+const kerberosIterations = 4096
+
+// kerberosConstant is n-fold("kerberos", 128), see RFC 3961 appendix A.1.
+var kerberosConstant = []byte{
+	0x6b, 0x65, 0x72, 0x62, 0x65, 0x72, 0x6f, 0x73,
+	0x7b, 0x9b, 0x5b, 0x2b, 0x93, 0x13, 0x2b, 0x93,
+}
+
+// kerberosEnctypes maps the supported encryption types to their key length in bytes.
+var kerberosEnctypes = map[string]int{
+	"aes128-cts-hmac-sha1-96": 16,
+	"aes256-cts-hmac-sha1-96": 32,
+}
+
+// kerberosSalt builds the default salt of a principal: the realm followed by
+// every component of the principal name, without separators.
+func kerberosSalt(principal, realm string) string {
+	return realm + strings.Join(strings.Split(principal, "/"), "")
+}
+
+// kerberosStringToKey implements the RFC 3962 string-to-key of the AES enctypes:
+// DK(PBKDF2-HMAC-SHA1(password, salt), "kerberos").
+func kerberosStringToKey(password, salt string, iterations, keyLen int) []byte {
+	tkey := pbkdf2.Key([]byte(password), []byte(salt), iterations, keyLen, sha1.New)
+
+	// DR encrypts the folded constant and keeps encrypting its own output.
+	// A single block needs no CBC or ciphertext stealing, and random-to-key
+	// is the identity for AES.
+	block, _ := aes.NewCipher(tkey)
+	key := make([]byte, 0, keyLen)
+	in := kerberosConstant
+	for len(key) < keyLen {
+		out := make([]byte, aes.BlockSize)
+		block.Encrypt(out, in)
+		key = append(key, out...)
+		in = out
+	}
+	return key[:keyLen]
+}
@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	_ function.Function = &KerberosStringToKeyFunction{}
+)
+
+func NewKerberosStringToKeyFunction() function.Function {
+	return &KerberosStringToKeyFunction{}
+}
+
+type KerberosStringToKeyFunction struct{}
+
+func (f *KerberosStringToKeyFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "kerberos_string_to_key"
+}
+
+func (f *KerberosStringToKeyFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Derives a Kerberos AES key from a password.",
+		MarkdownDescription: "Runs the RFC 3962 string-to-key of the AES enctypes with the default salt and 4096 iterations, " +
+			"as `kinit` and `ktutil` do for a principal without special salt or s2kparams. Returns the key hex encoded, " +
+			"ready for a keytab entry. The key version number only labels the entry and does not enter the key.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "principal",
+				MarkdownDescription: "The principal name without realm, e.g. `HTTP/www.example.com`.",
+			},
+			function.StringParameter{
+				Name:                "realm",
+				MarkdownDescription: "The realm, e.g. `EXAMPLE.COM`.",
+			},
+			function.StringParameter{
+				Name:                "password",
+				MarkdownDescription: "The password of the principal.",
+			},
+			function.StringParameter{
+				Name:                "enctype",
+				MarkdownDescription: "The encryption type: `aes128-cts-hmac-sha1-96` or `aes256-cts-hmac-sha1-96`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *KerberosStringToKeyFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var principal, realm, password, enctype string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &principal, &realm, &password, &enctype))
+	if resp.Error != nil {
+		return
+	}
+
+	keyLen, ok := kerberosEnctypes[enctype]
+	if !ok {
+		resp.Error = function.NewArgumentFuncError(3, "enctype must be aes128-cts-hmac-sha1-96 or aes256-cts-hmac-sha1-96")
+		return
+	}
+
+	key := kerberosStringToKey(password, kerberosSalt(principal, realm), kerberosIterations, keyLen)
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, hex.EncodeToString(key)))
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKerberosStringToKeyFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "aes256" {
+  value = provider::pbkdf2::kerberos_string_to_key("raeburn", "ATHENA.MIT.EDU", "password", "aes256-cts-hmac-sha1-96")
+}
+
+output "aes128" {
+  value = provider::pbkdf2::kerberos_string_to_key("HTTP/www.example.com", "EXAMPLE.COM", "password", "aes128-cts-hmac-sha1-96")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("aes256", "01b897121d933ab44b47eb5494db15e50eb74530dbdae9b634d65020ff5d88c1"),
+					resource.TestCheckOutput("aes128", "ab5d0f096394247390fb4da95464737e"),
+				),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::kerberos_string_to_key("raeburn", "ATHENA.MIT.EDU", "password", "des3-cbc-sha1")
+}
+`,
+				ExpectError: regexp.MustCompile(`enctype must be aes128-cts-hmac-sha1-96 or aes256-cts-hmac-sha1-96`),
+			},
+		},
+	})
+}
@@ -0,0 +1,35 @@
+package provider
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestKerberosStringToKey(t *testing.T) {
+	// Test vectors from RFC 3962 appendix B.
+	cases := []struct {
+		password   string
+		salt       string
+		iterations int
+		keyLen     int
+		expected   string
+	}{
+		{"password", "ATHENA.MIT.EDUraeburn", 1, 16, "42263c6e89f4fc28b8df68ee09799f15"},
+		{"password", "ATHENA.MIT.EDUraeburn", 1, 32, "fe697b52bc0d3ce14432ba036a92e65bbb52280990a2fa27883998d72af30161"},
+		{"password", "ATHENA.MIT.EDUraeburn", 1200, 16, "4c01cd46d632d01e6dbe230a01ed642a"},
+		{"password", "ATHENA.MIT.EDUraeburn", 1200, 32, "55a6ac740ad17b4846941051e1e8b0a7548d93b0ab30a8bc3ff16280382b8c2a"},
+	}
+
+	for _, c := range cases {
+		actual := hex.EncodeToString(kerberosStringToKey(c.password, c.salt, c.iterations, c.keyLen))
+		if actual != c.expected {
+			t.Errorf("%d iterations, %d bytes: got %s, want %s", c.iterations, c.keyLen, actual, c.expected)
+		}
+	}
+}
+
+func TestKerberosSalt(t *testing.T) {
+	if salt := kerberosSalt("host/www.example.com", "EXAMPLE.COM"); salt != "EXAMPLE.COMhostwww.example.com" {
+		t.Errorf("got %q", salt)
+	}
+}
@@ -0,0 +1,182 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &KeyDataSource{}
+	_ datasource.DataSourceWithConfigure = &KeyDataSource{}
+)
+
+func NewKeyDataSource() datasource.DataSource {
+	return &KeyDataSource{}
+}
+
+type KeyDataSource struct {
+	provider *pbkdf2ProviderData
+}
+
+func (d *KeyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key"
+}
+
+func (d *KeyDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*pbkdf2ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *pbkdf2ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = data
+}
+
+func (d *KeyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Derives a PBKDF2 key from a password and a given salt on every read, without managing any state of its own, " +
+			"to verify a stored hash or derive keys in read-only pipelines. The provider's `pepper` and `derivation_context` apply as for `pbkdf2_key`, " +
+			"so the same inputs derive the same `key` and `result`.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to derive from.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded salt, as exposed by the `salt` attribute of `pbkdf2_key`.",
+				Required:            true,
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of iterations. Defaults to the provider's `default_iterations`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "The pseudorandom function to use: " + markdownList(pbkdf2kit.PRFNames()) + ". Defaults to the provider's `default_prf`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PRFNames()...),
+				},
+			},
+			"key_length": schema.Int64Attribute{
+				MarkdownDescription: "Length in bytes of `key`. Between 1 and 1024. Defaults to the output size of `prf`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1024),
+				},
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Output format of `result`, as for `pbkdf2_key`. Defaults to the provider's `default_format`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"format_preset": schema.StringAttribute{
+				MarkdownDescription: "Render `result` in the stored format of a known consumer instead of a `format` template: " + markdownList(pbkdf2kit.PresetNames()) + ".",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PresetNames()...),
+					stringvalidator.ConflictsWith(path.MatchRoot("format")),
+				},
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"result": schema.StringAttribute{
+				MarkdownDescription: "The formatted key result.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type KeyDataSourceData struct {
+	Password     types.String `tfsdk:"password"`
+	Salt         types.String `tfsdk:"salt"`
+	Iterations   types.Int64  `tfsdk:"iterations"`
+	Prf          types.String `tfsdk:"prf"`
+	KeyLength    types.Int64  `tfsdk:"key_length"`
+	Format       types.String `tfsdk:"format"`
+	FormatPreset types.String `tfsdk:"format_preset"`
+	Key          types.String `tfsdk:"key"`
+	Result       types.String `tfsdk:"result"`
+}
+
+func (d *KeyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data KeyDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveKeyData(&data, d.provider, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// deriveKeyData fills in the defaults, key and result of data from its password and base64 encoded salt.
+// The derivation is shared with pbkdf2_key, so both render the same result from the same inputs.
+func deriveKeyData(data *KeyDataSourceData, provider *pbkdf2ProviderData, diags *diag.Diagnostics) {
+	salt, err := base64.StdEncoding.DecodeString(data.Salt.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("salt"), "Invalid Salt", "The salt is not valid base64.")
+		return
+	}
+	if data.Iterations.IsNull() {
+		data.Iterations = types.Int64Value(provider.defaultIterations())
+	}
+	if data.Prf.IsNull() {
+		data.Prf = types.StringValue(provider.defaultPRF())
+	}
+	if data.Format.IsNull() {
+		data.Format = types.StringValue(provider.defaultFormat())
+	}
+	if attr, conflict := contextFormatConflict(KeyResourceData{Format: data.Format, FormatPreset: data.FormatPreset}, provider); conflict {
+		diags.AddAttributeError(attr, "Derivation Context Conflict", contextFormatDetail)
+		return
+	}
+
+	dk, result, err := derive(KeyResourceData{
+		Prf:          data.Prf,
+		Iterations:   data.Iterations,
+		KeyLength:    data.KeyLength,
+		Format:       data.Format,
+		FormatPreset: data.FormatPreset,
+		PepperMode:   types.StringValue("hmac"),
+	}, provider, data.Password.ValueString(), salt)
+	if err != nil {
+		addFormatError(diags, err, provider.redactErrors())
+		return
+	}
+
+	data.Key = types.StringValue(base64.StdEncoding.EncodeToString(dk))
+	data.Result = types.StringValue(result)
+}
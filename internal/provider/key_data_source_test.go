@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccKeyDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyDataSourceConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_key.default", "prf", "hmac-sha256"),
+					resource.TestCheckResourceAttr("data.pbkdf2_key.default", "key", "8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="),
+					resource.TestCheckResourceAttr("data.pbkdf2_key.default", "result", "c2FsdHNhbHRzYWx0c2FsdA==:8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="),
+					resource.TestCheckResourceAttr("data.pbkdf2_key.tomcat", "result", "73616c7473616c7473616c7473616c74$1000$45c3ea0e4d8bbf4ffb69800319a0c404"),
+				),
+			},
+			{
+				Config: `
+data "pbkdf2_key" "test" {
+  password = "one"
+  salt     = "not base64!"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Salt`),
+			},
+		},
+	})
+}
+
+const testAccKeyDataSourceConfig = `
+data "pbkdf2_key" "default" {
+  password   = "one"
+  salt       = "c2FsdHNhbHRzYWx0c2FsdA=="
+  iterations = 1000
+}
+
+data "pbkdf2_key" "tomcat" {
+  password      = "one"
+  salt          = "c2FsdHNhbHRzYWx0c2FsdA=="
+  iterations    = 1000
+  prf           = "hmac-sha512"
+  key_length    = 16
+  format_preset = "tomcat"
+}
+`
@@ -0,0 +1,158 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource              = &KeyEphemeralResource{}
+	_ ephemeral.EphemeralResourceWithConfigure = &KeyEphemeralResource{}
+)
+
+func NewKeyEphemeralResource() ephemeral.EphemeralResource {
+	return &KeyEphemeralResource{}
+}
+
+type KeyEphemeralResource struct {
+	provider *pbkdf2ProviderData
+}
+
+// KeyEphemeralResourceData is the pbkdf2_key data source with a salt that is generated when not given.
+type KeyEphemeralResourceData struct {
+	KeyDataSourceData
+	SaltLength types.Int64 `tfsdk:"salt_length"`
+}
+
+func (r *KeyEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_key"
+}
+
+func (r *KeyEphemeralResource) Configure(_ context.Context, req ephemeral.ConfigureRequest, resp *ephemeral.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*pbkdf2ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Ephemeral Resource Configure Type",
+			fmt.Sprintf("Expected *pbkdf2ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.provider = data
+}
+
+func (r *KeyEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "PBKDF2 key derived whenever it is opened and never written to state or plan, to hand to write-only attributes of other resources " +
+			"when neither the password, the salt nor the key may be persisted. Without a `salt` every run generates a new one, so whatever consumes the key must store `result` or the salt with it. " +
+			"The provider's `pepper` and `derivation_context` apply as for `pbkdf2_key`.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to derive from.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded salt. Generated from `salt_length` random bytes when not set.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"salt_length": schema.Int64Attribute{
+				MarkdownDescription: "The length of the generated salt in bytes. Defaults to the provider's `default_salt_length`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1024),
+					int64validator.ConflictsWith(path.MatchRoot("salt")),
+				},
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of iterations. Defaults to the provider's `default_iterations`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "The pseudorandom function to use: " + markdownList(pbkdf2kit.PRFNames()) + ". Defaults to the provider's `default_prf`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PRFNames()...),
+				},
+			},
+			"key_length": schema.Int64Attribute{
+				MarkdownDescription: "Length in bytes of `key`. Between 1 and 1024. Defaults to the output size of `prf`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1024),
+				},
+			},
+			"format": schema.StringAttribute{
+				MarkdownDescription: "Output format of `result`, as for `pbkdf2_key`. Defaults to the provider's `default_format`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"format_preset": schema.StringAttribute{
+				MarkdownDescription: "Render `result` in the stored format of a known consumer instead of a `format` template: " + markdownList(pbkdf2kit.PresetNames()) + ".",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PresetNames()...),
+					stringvalidator.ConflictsWith(path.MatchRoot("format")),
+				},
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"result": schema.StringAttribute{
+				MarkdownDescription: "The formatted key result.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+func (r *KeyEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data KeyEphemeralResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Salt.IsNull() {
+		if data.SaltLength.IsNull() {
+			data.SaltLength = types.Int64Value(r.provider.defaultSaltLength())
+		}
+		salt, err := newSalt(data.SaltLength.ValueInt64())
+		if err != nil {
+			resp.Diagnostics.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(base64.StdEncoding.EncodeToString(salt))
+	}
+	deriveKeyData(&data.KeyDataSourceData, r.provider, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
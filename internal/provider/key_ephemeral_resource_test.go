@@ -0,0 +1,58 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-testing/echoprovider"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKeyEphemeralResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_10_0),
+		},
+		// The echo provider stores what it is given, so the ephemeral result can be checked.
+		ProtoV6ProviderFactories: map[string]func() (tfprotov6.ProviderServer, error){
+			"pbkdf2": testAccProtoV6ProviderFactories["pbkdf2"],
+			"echo":   echoprovider.NewProviderServer(),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+ephemeral "pbkdf2_key" "given" {
+  password   = "one"
+  salt       = "c2FsdHNhbHRzYWx0c2FsdA=="
+  iterations = 1000
+}
+
+ephemeral "pbkdf2_key" "generated" {
+  password    = "one"
+  salt_length = 24
+  iterations  = 1000
+}
+
+provider "echo" {
+  data = {
+    given     = ephemeral.pbkdf2_key.given
+    generated = ephemeral.pbkdf2_key.generated
+  }
+}
+
+resource "echo" "test" {}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("echo.test", "data.given.key", "8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="),
+					resource.TestCheckResourceAttr("echo.test", "data.given.result", "c2FsdHNhbHRzYWx0c2FsdA==:8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="),
+					resource.TestCheckResourceAttr("echo.test", "data.given.prf", "hmac-sha256"),
+					resource.TestMatchResourceAttr("echo.test", "data.generated.salt", regexp.MustCompile(`^[A-Za-z0-9+/]{32}$`)),
+					resource.TestMatchResourceAttr("echo.test", "data.generated.key", regexp.MustCompile(`^[A-Za-z0-9+/]{43}=$`)),
+				),
+			},
+		},
+	})
+}
@@ -1,113 +1,630 @@
 package provider
 
 import (
-	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/rand"
 	"crypto/sha256"
-	"crypto/sha512"
+	"crypto/subtle"
 	"encoding/base64"
 	"encoding/binary"
-	"hash"
-	"text/template"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
 
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"golang.org/x/crypto/pbkdf2"
+	zxcvbn "github.com/nbutton23/zxcvbn-go"
+	"golang.org/x/crypto/hkdf"
 )
 
 var (
-	_ resource.Resource = &KeyResource{}
+	_ resource.Resource                   = &KeyResource{}
+	_ resource.ResourceWithConfigure      = &KeyResource{}
+	_ resource.ResourceWithModifyPlan     = &KeyResource{}
+	_ resource.ResourceWithUpgradeState   = &KeyResource{}
+	_ resource.ResourceWithValidateConfig = &KeyResource{}
 )
 
 func NewKeyResource() resource.Resource {
 	return &KeyResource{}
 }
 
-type KeyResource struct{}
+type KeyResource struct {
+	provider *pbkdf2ProviderData
+}
 
 func (r *KeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
 	resp.TypeName = req.ProviderTypeName + "_key"
 }
 
+func (r *KeyResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*pbkdf2ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Resource Configure Type",
+			fmt.Sprintf("Expected *pbkdf2ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	r.provider = data
+}
+
 func (r *KeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "PBKDF2 derived key.",
+		Version:             2,
 
 		Attributes: map[string]schema.Attribute{
 			"iterations": schema.Int64Attribute{
-				MarkdownDescription: "Number of iterations.",
+				MarkdownDescription: "Number of iterations. Defaults to the provider's `default_iterations`.",
 				Optional:            true,
 				Computed:            true,
-				Default:             int64default.StaticInt64(100000),
+			},
+			"target_duration_ms": schema.Int64Attribute{
+				MarkdownDescription: "Calibrate `iterations` on create so a single derivation takes roughly this many milliseconds on the applying machine. The calibrated count is pinned in state and only recalibrated when this value changes. " +
+					"It is never below the provider's minimum, 1000 with `fips_mode`. Ignored when `iterations` is set.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"security_level": schema.StringAttribute{
+				MarkdownDescription: "Pick `iterations` from a parameter set maintained by the provider instead: `interactive` for logins, following the OWASP Password Storage Cheat Sheet, " +
+					"`moderate` and `sensitive` for secrets that are derived rarely and can afford twice and five times the cost. The count depends on `prf`:" + securityLevelsMarkdown(),
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(securityLevelNames...),
+					stringvalidator.ConflictsWith(path.MatchRoot("iterations"), path.MatchRoot("target_duration_ms")),
+				},
 			},
 			"format": schema.StringAttribute{
-				MarkdownDescription: "Output format; will additionally be base64 encoded.",
+				MarkdownDescription: "Output format; will additionally be base64 encoded. A Go template over `.Iterations`, `.Salt`, `.Key`, `.SaltLength`, `.KeyLength`, " +
+					"`.HashAlgorithm` (the bare hash name of `prf`, such as `sha256`) and `.Password` (empty for `password_wo`) with the functions " +
+					markdownList(pbkdf2kit.FormatFuncNames()) + ", e.g. `{{ hexenc .Key }}` for a hex encoded key or `pbkdf2_{{ .HashAlgorithm }}$...` for the algorithm name. Defaults to the provider's `default_format`.",
+				Optional: true,
+				Computed: true,
+			},
+			"format_file": schema.StringAttribute{
+				MarkdownDescription: "Path to a file holding the `format` template, e.g. `\"${path.module}/key.tmpl\"`, read during plan. The template is stored in `format`, so editing the file re-renders `result` like editing `format` does.",
 				Optional:            true,
-				Computed:            true,
-				Default:             stringdefault.StaticString("{{ printf \"%s:%s\" (b64enc .Salt) (b64enc .Key) }}"),
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("format")),
+				},
+			},
+			"format_preset": schema.StringAttribute{
+				MarkdownDescription: "Render `result` in the stored format of a known consumer instead of a `format` template:" + formatPresetsMarkdown(),
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PresetNames()...),
+					stringvalidator.ConflictsWith(path.MatchRoot("format"), path.MatchRoot("format_file")),
+				},
+			},
+			"expected_pattern": schema.StringAttribute{
+				MarkdownDescription: "Regular expression every formatted result must match, failing the apply otherwise, as a safety net for hand-written `format` templates. " +
+					"Anchor it with `^` and `$` to match the whole result.",
+				Optional: true,
 			},
 			"password": schema.StringAttribute{
-				MarkdownDescription: "The password input to encrypt.",
-				Required:            true,
+				MarkdownDescription: "The password input to encrypt. It is stored in state; use `password_wo` to keep it out. Exactly one of `password` and `password_wo` must be set.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ExactlyOneOf(path.MatchRoot("password"), path.MatchRoot("password_wo")),
+				},
+			},
+			"password_wo": schema.StringAttribute{
+				MarkdownDescription: "Write-only variant of `password`, never stored in state, so only `password_wo_version` records which password the key derives from. " +
+					"As the password is unknown on refresh, such keys are not checked by the provider's `verify_on_refresh`, and their key is always `(known after apply)`. Requires Terraform 1.11 or later.",
+				Optional:  true,
+				Sensitive: true,
+				WriteOnly: true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("password_wo_version")),
+					stringvalidator.ConflictsWith(path.MatchRoot("next_password")),
+				},
+			},
+			"password_wo_version": schema.StringAttribute{
+				MarkdownDescription: "Change to re-derive the key from the current `password_wo`, which Terraform can't diff itself. A changed version counts as a changed `password` for `replace_on`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("password_wo")),
+				},
+			},
+			"old_passwords": schema.ListAttribute{
+				MarkdownDescription: "Previous passwords to produce history entries for, each hashed with an independent salt.",
+				ElementType:         types.StringType,
+				Optional:            true,
 				Sensitive:           true,
 			},
+			"next_password": schema.StringAttribute{
+				MarkdownDescription: "The upcoming password. Its key is kept stable in the `next_*` attributes until promoted, so consumers can accept both keys during a rollout.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"promotions": schema.Int64Attribute{
+				MarkdownDescription: "Increment to promote the next key to current. `password` must be set to the previous `next_password` in the same change.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(0),
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "The pseudorandom function to use: " + markdownList(pbkdf2kit.PRFNames()) + ". Defaults to the provider's `default_prf`. " +
+					"The `keyed-blake2b-*` PRFs use BLAKE2b's native keyed mode with the password as key instead of HMAC, for systems following that convention; " +
+					"they take passwords of at most 64 bytes, so set `pre_hash` for longer ones, and none of the format presets support them. " +
+					"The `hmac-streebog*` PRFs are HMAC over the GOST R 34.11-2012 hash as in R 50.1.111-2016, for deployments bound to GOST algorithms.",
+				Optional: true,
+				Computed: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PRFNames()...),
+					stringvalidator.ConflictsWith(path.MatchRoot("hash_algorithm")),
+				},
+			},
 			"hash_algorithm": schema.StringAttribute{
-				MarkdownDescription: "The hash function to use.",
+				MarkdownDescription: "The hash function to use, as the bare hash name of `prf`: " + markdownList(prfAliases()) + ". The key length follows the digest size.",
+				DeprecationMessage:  "Use prf instead, e.g. hmac-sha256 instead of sha256.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(prfAliases()...),
+				},
+			},
+			"replace_on": schema.ListAttribute{
+				MarkdownDescription: "Inputs whose change generates a new salt: " + markdownList(replaceOnInputs) + ". Changes to any other input re-derive the key in place with the stored salt. A changed `salt_length` or `salt_charset` always generates a new salt. " +
+					"Defaults to " + markdownList(replaceOnDefaults) + ", so a changed `format` or `format_preset` only re-renders `result` from the stored salt and key.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Default:     listdefault.StaticValue(types.ListValueMust(types.StringType, replaceOnDefault())),
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.OneOf(replaceOnInputs...)),
+				},
+			},
+			"keepers": schema.MapAttribute{
+				MarkdownDescription: "Arbitrary values that replace the resource, and so generate a new salt and key, when any of them changes, like the `keepers` of the `random` provider. " +
+					"Set `replace_on = []` to have only the keepers rotate the salt.",
+				ElementType: types.StringType,
+				Optional:    true,
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.RequiresReplace(),
+				},
+			},
+			"pre_hash": schema.BoolAttribute{
+				MarkdownDescription: "Hash passwords with SHA-512 and derive from the raw 64 byte digest, for verifiers that pre-hash and to treat very long or binary passwords the same everywhere. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"pepper_mode": schema.StringAttribute{
+				MarkdownDescription: "How the pepper is applied to passwords: `hmac` derives from `HMAC(pepper, password)` using the hash of `prf`, `concat` from `password || pepper`. Defaults to `hmac`; ignored without a pepper.",
 				Optional:            true,
 				Computed:            true,
-				Default:             stringdefault.StaticString("sha256"),
+				Default:             stringdefault.StaticString("hmac"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("hmac", "concat"),
+				},
+			},
+			"pepper": schema.StringAttribute{
+				MarkdownDescription: "Pepper for this key, overriding the provider `pepper`, e.g. one per tenant. It is stored in state; use `pepper_wo` to keep it out.",
+				Optional:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("pepper_wo")),
+				},
+			},
+			"pepper_wo": schema.StringAttribute{
+				MarkdownDescription: "Write-only variant of `pepper`, never stored in state. As the pepper is unknown on refresh, such keys are not checked by the provider's `verify_on_refresh`. Requires Terraform 1.11 or later.",
+				Optional:            true,
+				Sensitive:           true,
+				WriteOnly:           true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("pepper_wo_version")),
+				},
+			},
+			"pepper_wo_version": schema.StringAttribute{
+				MarkdownDescription: "Change to re-derive the key with the current `pepper_wo`, which Terraform can't diff itself.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.AlsoRequires(path.MatchRoot("pepper_wo")),
+				},
 			},
 			"salt_length": schema.Int64Attribute{
-				MarkdownDescription: "The length of the generated salt value.",
+				MarkdownDescription: "The length of the generated salt value. Defaults to the provider's `default_salt_length`.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"salt_charset": schema.StringAttribute{
+				MarkdownDescription: "Characters of the generated salt: `bytes` for raw random bytes, or `hex` or `alphanumeric` for a printable salt of `salt_length` characters, " +
+					"used as is, for verifiers that read the salt from a text file. A changed `salt_charset` always generates a new salt. Defaults to `bytes`.",
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString("bytes"),
+				Validators: []validator.String{
+					stringvalidator.OneOf(saltCharsetNames...),
+				},
+			},
+			"salt_seed": schema.StringAttribute{
+				MarkdownDescription: "Derive the salt as `HKDF-SHA256(salt_seed)` instead of generating it randomly, so identical configurations converge on identical keys, e.g. in disconnected environments. " +
+					"Keys sharing a seed share their salt, so use a distinct seed per key. Changing the seed always generates a new salt. " +
+					"When every input is known during plan, the salt, key and result are computed during plan instead of being `(known after apply)`, unless `recipients` or `pepper_wo` is set.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(16),
+					stringvalidator.ConflictsWith(path.MatchRoot("next_password"), path.MatchRoot("old_passwords")),
+				},
+			},
+			"salt_input": schema.StringAttribute{
+				MarkdownDescription: "The salt to derive with instead of generating one, such as the salt of a hash migrated into Terraform, encoded as chosen by `salt_input_encoding`. " +
+					"`salt_length` and `salt_charset` don't apply to it. The derivation is deterministic, so like with `salt_seed` the key and result are computed during plan when every input is known.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("salt_seed"), path.MatchRoot("existing_hash"), path.MatchRoot("next_password")),
+				},
+			},
+			"salt_input_encoding": schema.StringAttribute{
+				MarkdownDescription: "Encoding of `salt_input`: `base64` or `hex`. Defaults to `base64`.",
 				Optional:            true,
 				Computed:            true,
-				Default:             int64default.StaticInt64(16),
+				Default:             stringdefault.StaticString("base64"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("base64", "hex"),
+				},
+			},
+			"existing_hash": schema.StringAttribute{
+				MarkdownDescription: "A hash of `password` already deployed outside Terraform, to adopt instead of deriving a fresh one when the resource is created. " +
+					"It is adopted, salt included, when `password` derives to exactly this hash with the configured parameters and `format`; otherwise a new salt is generated and a plan warning says so. " +
+					"The salt is recovered from the default `<b64 salt>:<b64 key>` result, PHC, passlib and Django PBKDF2 hashes, and SCRAM-SHA-256 verifiers. Ignored once the resource exists.",
+				Optional:  true,
+				Sensitive: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("salt_seed")),
+				},
+			},
+			"key_length": schema.Int64Attribute{
+				MarkdownDescription: "Length in bytes of `key`, such as `24` for a 3DES key, or more than the output size of `prf` to derive several PBKDF2 blocks. " +
+					"Between 1 and 1024. Defaults to the output size of `prf`. Use `cipher_key_length` with `iv_length` to derive a key and IV pair instead.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1024),
+					int64validator.ConflictsWith(path.MatchRoot("iv_length")),
+				},
+			},
+			"cipher_key_length": schema.Int64Attribute{
+				MarkdownDescription: "Length in bytes of `cipher_key` when `iv_length` is set. Defaults to the output size of `prf`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+					int64validator.AlsoRequires(path.MatchRoot("iv_length")),
+				},
+			},
+			"iv_length": schema.Int64Attribute{
+				MarkdownDescription: "Derive a cipher key and IV pair: `key` then covers `cipher_key_length` plus this many bytes, split into `cipher_key` and `iv` in that order.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				MarkdownDescription: "Make destroying this key fail, since data protected by it can't be recovered once it is gone. Set `force_destroy` and apply before destroying a protected key.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"force_destroy": schema.BoolAttribute{
+				MarkdownDescription: "Allow destroying the key despite `deletion_protection`. Must be applied before the destroy to take effect.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"sql_role": schema.StringAttribute{
+				MarkdownDescription: "Role to render `sql_statement` for. The name is quoted, so it is case sensitive.",
+				Optional:            true,
+			},
+			"sql_dialect": schema.StringAttribute{
+				MarkdownDescription: "SQL dialect of `sql_statement`: " + markdownList(sqlDialectNames()) + ". Defaults to `postgresql`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("postgresql"),
+				Validators: []validator.String{
+					stringvalidator.OneOf(sqlDialectNames()...),
+				},
+			},
+			"description": schema.StringAttribute{
+				MarkdownDescription: "Free-form description of what the key is for, repeated in `attestation`. Changing it keeps the key.",
+				Optional:            true,
+			},
+			"tags": schema.MapAttribute{
+				MarkdownDescription: "Free-form labels, such as owner or system, repeated in `attestation`. Changing them keeps the key.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"recipients": schema.ListAttribute{
+				MarkdownDescription: "age recipients (`age1...`) or SSH public keys (`ssh-ed25519`, `ssh-rsa`) to encrypt the key material to before it is written to state. " +
+					"`key` and `result` are then null and only readable by decrypting `encrypted_key` and `encrypted_result`, e.g. with `age --decrypt`. " +
+					"As the key can't be re-derived from state, such keys are not checked by the provider's `verify_on_refresh`.",
+				ElementType: types.StringType,
+				Optional:    true,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(
+						path.MatchRoot("next_password"),
+						path.MatchRoot("old_passwords"),
+						path.MatchRoot("iv_length"),
+						path.MatchRoot("sql_role"),
+					),
+				},
+			},
+			"share_threshold": schema.Int64Attribute{
+				MarkdownDescription: "Split the key into one Shamir share per entry of `recipients`, any `share_threshold` of which recover it, so no single recipient holds the key. " +
+					"Each share is encrypted to its recipient only, in `encrypted_shares`, and `encrypted_key` and `encrypted_result` are null.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.Between(2, 255),
+					int64validator.AlsoRequires(path.MatchRoot("recipients")),
+				},
 			},
 			"salt": schema.StringAttribute{
-				MarkdownDescription: "The generated salt value.",
+				MarkdownDescription: "The base64 encoded salt.",
 				Computed:            true,
 				Sensitive:           true,
 			},
 			"key": schema.StringAttribute{
-				MarkdownDescription: "The generated key value.",
+				MarkdownDescription: "The base64 encoded key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"cipher_key": schema.StringAttribute{
+				MarkdownDescription: "The leading `cipher_key_length` bytes of `key`, base64 encoded. Null unless `iv_length` is set.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"iv": schema.StringAttribute{
+				MarkdownDescription: "The trailing `iv_length` bytes of `key`, base64 encoded. Null unless `iv_length` is set.",
 				Computed:            true,
 				Sensitive:           true,
 			},
+			"kcv": schema.StringAttribute{
+				MarkdownDescription: "Key check value of `key`, or of `cipher_key` when `iv_length` is set: the first 3 bytes of the AES encryption of a zero block, in upper case hex. " +
+					"Compare it with the value an HSM or peer shows to confirm both ends hold the same key without revealing it. Null unless the key is 16, 24 or 32 bytes.",
+				Computed: true,
+			},
 			"result": schema.StringAttribute{
 				MarkdownDescription: "The formatted key result.",
 				Computed:            true,
 				Sensitive:           true,
 			},
+			"sql_statement": schema.StringAttribute{
+				MarkdownDescription: "Statement setting `result` as the stored password of `sql_role`, e.g. `ALTER ROLE \"app\" PASSWORD 'SCRAM-SHA-256$...'` with `format_preset = \"postgresql_scram\"`. " +
+					"The literal assumes `standard_conforming_strings`, the default since PostgreSQL 9.1. Null unless `sql_role` is set.",
+				Computed:  true,
+				Sensitive: true,
+			},
+			"encrypted_key": schema.StringAttribute{
+				MarkdownDescription: "The raw key bytes as an ASCII armored age file encrypted to `recipients`. Null unless `recipients` is set.",
+				Computed:            true,
+			},
+			"encrypted_result": schema.StringAttribute{
+				MarkdownDescription: "`result` as an ASCII armored age file encrypted to `recipients`. Null unless `recipients` is set.",
+				Computed:            true,
+			},
+			"encrypted_shares": schema.ListAttribute{
+				MarkdownDescription: "The Shamir shares of the raw key bytes as ASCII armored age files, each encrypted to the entry of `recipients` at the same index. " +
+					"A decrypted share holds a value for every key byte followed by its x coordinate, the layout of HashiCorp Vault's `shamir` package. Null unless `share_threshold` is set.",
+				ElementType: types.StringType,
+				Computed:    true,
+			},
+			"old_results": schema.ListAttribute{
+				MarkdownDescription: "The formatted results for `old_passwords`, in the same order.",
+				ElementType:         types.StringType,
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"old_salts": schema.ListAttribute{
+				MarkdownDescription: "The base64 encoded salts of `old_passwords`, in the same order. An entry that stays in `old_passwords` keeps its salt, so its result only changes with the derivation parameters.",
+				ElementType:         types.StringType,
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"attestation": schema.StringAttribute{
+				MarkdownDescription: "JSON record of the derivation parameters, provider version and creation time of the current key, which updates that keep its salt and key leave as is, along with its `description` and `tags`, free of secret material, for compliance evidence.",
+				Computed:            true,
+			},
+			"integrity_tag": schema.StringAttribute{
+				MarkdownDescription: "HMAC over the stored salt, key and derivation parameters, keyed by the provider's `integrity_key` and verified on refresh. Null when no `integrity_key` is configured.",
+				Computed:            true,
+			},
+			"next_salt": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded salt of the next key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"next_key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded next key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"next_result": schema.StringAttribute{
+				MarkdownDescription: "The formatted next key result.",
+				Computed:            true,
+				Sensitive:           true,
+			},
 		},
 	}
 }
 
 type KeyResourceData struct {
-	Iterations    types.Int64  `tfsdk:"iterations"`
-	Format        types.String `tfsdk:"format"`
-	Password      types.String `tfsdk:"password"`
-	HashAlgorithm types.String `tfsdk:"hash_algorithm"`
-	SaltLength    types.Int64  `tfsdk:"salt_length"`
-	Salt          types.String `tfsdk:"salt"`
-	Key           types.String `tfsdk:"key"`
-	Result        types.String `tfsdk:"result"`
+	Iterations         types.Int64  `tfsdk:"iterations"`
+	TargetDurationMs   types.Int64  `tfsdk:"target_duration_ms"`
+	SecurityLevel      types.String `tfsdk:"security_level"`
+	Format             types.String `tfsdk:"format"`
+	FormatFile         types.String `tfsdk:"format_file"`
+	FormatPreset       types.String `tfsdk:"format_preset"`
+	ExpectedPattern    types.String `tfsdk:"expected_pattern"`
+	Password           types.String `tfsdk:"password"`
+	PasswordWo         types.String `tfsdk:"password_wo"`
+	PasswordWoVersion  types.String `tfsdk:"password_wo_version"`
+	OldPasswords       types.List   `tfsdk:"old_passwords"`
+	NextPassword       types.String `tfsdk:"next_password"`
+	Promotions         types.Int64  `tfsdk:"promotions"`
+	Prf                types.String `tfsdk:"prf"`
+	HashAlgorithm      types.String `tfsdk:"hash_algorithm"`
+	ReplaceOn          types.List   `tfsdk:"replace_on"`
+	Keepers            types.Map    `tfsdk:"keepers"`
+	PreHash            types.Bool   `tfsdk:"pre_hash"`
+	PepperMode         types.String `tfsdk:"pepper_mode"`
+	Pepper             types.String `tfsdk:"pepper"`
+	PepperWo           types.String `tfsdk:"pepper_wo"`
+	PepperWoVersion    types.String `tfsdk:"pepper_wo_version"`
+	SaltLength         types.Int64  `tfsdk:"salt_length"`
+	SaltCharset        types.String `tfsdk:"salt_charset"`
+	SaltSeed           types.String `tfsdk:"salt_seed"`
+	SaltInput          types.String `tfsdk:"salt_input"`
+	SaltInputEncoding  types.String `tfsdk:"salt_input_encoding"`
+	ExistingHash       types.String `tfsdk:"existing_hash"`
+	KeyLength          types.Int64  `tfsdk:"key_length"`
+	CipherKeyLength    types.Int64  `tfsdk:"cipher_key_length"`
+	IvLength           types.Int64  `tfsdk:"iv_length"`
+	DeletionProtection types.Bool   `tfsdk:"deletion_protection"`
+	ForceDestroy       types.Bool   `tfsdk:"force_destroy"`
+	SQLRole            types.String `tfsdk:"sql_role"`
+	SQLDialect         types.String `tfsdk:"sql_dialect"`
+	Description        types.String `tfsdk:"description"`
+	Tags               types.Map    `tfsdk:"tags"`
+	Recipients         types.List   `tfsdk:"recipients"`
+	ShareThreshold     types.Int64  `tfsdk:"share_threshold"`
+	Salt               types.String `tfsdk:"salt"`
+	Key                types.String `tfsdk:"key"`
+	CipherKey          types.String `tfsdk:"cipher_key"`
+	Iv                 types.String `tfsdk:"iv"`
+	Kcv                types.String `tfsdk:"kcv"`
+	Result             types.String `tfsdk:"result"`
+	SQLStatement       types.String `tfsdk:"sql_statement"`
+	EncryptedKey       types.String `tfsdk:"encrypted_key"`
+	EncryptedResult    types.String `tfsdk:"encrypted_result"`
+	EncryptedShares    types.List   `tfsdk:"encrypted_shares"`
+	OldResults         types.List   `tfsdk:"old_results"`
+	OldSalts           types.List   `tfsdk:"old_salts"`
+	Attestation        types.String `tfsdk:"attestation"`
+	IntegrityTag       types.String `tfsdk:"integrity_tag"`
+	NextSalt           types.String `tfsdk:"next_salt"`
+	NextKey            types.String `tfsdk:"next_key"`
+	NextResult         types.String `tfsdk:"next_result"`
 }
 
-type toFmt struct {
-	Iterations int
-	Salt       []byte
-	Key        []byte
+type KeyRequest struct {
+	Config   *tfsdk.Config
+	Plan     *tfsdk.Plan
+	State    *tfsdk.State
+	Provider *pbkdf2ProviderData
 }
 
-type KeyRequest struct {
-	Plan *tfsdk.Plan
+// attestation is the non-secret record of how the key material was derived.
+type attestation struct {
+	Algorithm       string            `json:"algorithm"`
+	PRF             string            `json:"prf"`
+	Iterations      int64             `json:"iterations"`
+	SaltLength      int64             `json:"salt_length"`
+	KeyLength       int               `json:"key_length"`
+	ProviderVersion string            `json:"provider_version"`
+	CreatedAt       string            `json:"created_at"`
+	Description     string            `json:"description,omitempty"`
+	Tags            map[string]string `json:"tags,omitempty"`
+}
+
+// securityLevelNames are the values of security_level, from cheapest to most expensive.
+var securityLevelNames = []string{"interactive", "moderate", "sensitive"}
+
+// securityLevels are the iterations of each security_level per prf. A keyed BLAKE2b iteration is a single
+// compression once an attacker caches the key block, against two for HMAC, so they get twice the iterations
+// of hmac-sha512, which has a compression function of similar cost. SHA-1 follows OWASP, SHA-224 and SHA-384
+// share the compression functions of SHA-256 and SHA-512, and SHA-3, HMAC-BLAKE2b and Streebog have 64 bit
+// designs at least as slow as SHA-512, so they get the iterations of hmac-sha512.
+var securityLevels = map[string]map[string]int64{
+	"interactive": {
+		"hmac-sha256": 600000, "hmac-sha512": 210000, "hmac-sha1": 1300000, "hmac-sha224": 600000, "hmac-sha384": 210000,
+		"hmac-sha3-256": 210000, "hmac-sha3-512": 210000, "hmac-blake2b-512": 210000, "keyed-blake2b-256": 420000, "keyed-blake2b-512": 420000,
+		"hmac-streebog256": 210000, "hmac-streebog512": 210000,
+	},
+	"moderate": {
+		"hmac-sha256": 1200000, "hmac-sha512": 420000, "hmac-sha1": 2600000, "hmac-sha224": 1200000, "hmac-sha384": 420000,
+		"hmac-sha3-256": 420000, "hmac-sha3-512": 420000, "hmac-blake2b-512": 420000, "keyed-blake2b-256": 840000, "keyed-blake2b-512": 840000,
+		"hmac-streebog256": 420000, "hmac-streebog512": 420000,
+	},
+	"sensitive": {
+		"hmac-sha256": 3000000, "hmac-sha512": 1050000, "hmac-sha1": 6500000, "hmac-sha224": 3000000, "hmac-sha384": 1050000,
+		"hmac-sha3-256": 1050000, "hmac-sha3-512": 1050000, "hmac-blake2b-512": 1050000, "keyed-blake2b-256": 2100000, "keyed-blake2b-512": 2100000,
+		"hmac-streebog256": 1050000, "hmac-streebog512": 1050000,
+	},
+}
+
+// securityLevelsMarkdown lists the iterations of every security level as markdown list items.
+func securityLevelsMarkdown() string {
+	var out strings.Builder
+	for _, level := range securityLevelNames {
+		out.WriteString("\n  - `" + level + "`:")
+		for i, name := range pbkdf2kit.PRFNames() {
+			if i > 0 {
+				out.WriteString(",")
+			}
+			out.WriteString(fmt.Sprintf(" %d for `%s`", securityLevels[level][name], name))
+		}
+		out.WriteString(".")
+	}
+	return out.String()
+}
+
+// replaceOnInputs are the inputs replace_on can name, in the order they are documented.
+var replaceOnInputs = []string{"password", "iterations", "prf", "format"}
+
+// replaceOnDefaults are the inputs of replace_on when it is not set. format is left out,
+// as a new format only re-renders result from the stored salt and key.
+var replaceOnDefaults = []string{"password", "iterations", "prf"}
+
+// replaceOnChanged reports whether an input named in replace_on differs between plan and state.
+var replaceOnChanged = map[string]func(plan, state KeyResourceData) bool{
+	"password": func(plan, state KeyResourceData) bool {
+		return !plan.Password.Equal(state.Password) || !plan.PasswordWoVersion.Equal(state.PasswordWoVersion)
+	},
+	"iterations": func(plan, state KeyResourceData) bool { return !plan.Iterations.Equal(state.Iterations) },
+	"prf":        func(plan, state KeyResourceData) bool { return !plan.Prf.Equal(state.Prf) },
+	"format": func(plan, state KeyResourceData) bool {
+		return !plan.Format.Equal(state.Format) || !plan.FormatPreset.Equal(state.FormatPreset)
+	},
+}
+
+func replaceOnDefault() []attr.Value {
+	values := make([]attr.Value, 0, len(replaceOnDefaults))
+	for _, input := range replaceOnDefaults {
+		values = append(values, types.StringValue(input))
+	}
+	return values
 }
 
 type KeyResponse struct {
@@ -121,83 +638,764 @@ func bin(len int, data int) string {
 	return string(bs[8-len:])
 }
 
-func b64enc(data []byte) string {
-	return base64.StdEncoding.EncodeToString(data)
+func newSalt(length int64) ([]byte, error) {
+	var salt = make([]byte, length)
+	_, err := rand.Read(salt[:])
+	return salt, err
+}
+
+// seededSalt derives a salt of the given length and charset from seed with HKDF-SHA256.
+func seededSalt(seed string, length int64, charset types.String) ([]byte, error) {
+	return saltFrom(hkdf.New(sha256.New, []byte(seed), nil, []byte("pbkdf2_key salt")), length, charset)
 }
 
-func getHashAlgorithm(hashFunc string) (int, func() hash.Hash) {
-	switch hashFunc {
-	case "sha256":
-		return 32, sha256.New
-	case "sha512":
-		return 64, sha512.New
-	default:
-		return 32, sha256.New
+// decodeSaltInput decodes salt_input as chosen by salt_input_encoding, which is null in config when not set.
+func decodeSaltInput(data KeyResourceData) ([]byte, error) {
+	if data.SaltInputEncoding.ValueString() == "hex" {
+		return hex.DecodeString(data.SaltInput.ValueString())
 	}
+	return base64.StdEncoding.DecodeString(data.SaltInput.ValueString())
 }
 
-func generate(ctx context.Context, req KeyRequest, resp *KeyResponse) {
-	var plan KeyResourceData
-	diags := req.Plan.Get(ctx, &plan)
-	resp.Diagnostics.Append(diags...)
+// saltCharsetNames are the values of salt_charset.
+var saltCharsetNames = []string{"bytes", "hex", "alphanumeric"}
+
+// saltCharsets are the characters of the printable salt charsets.
+var saltCharsets = map[string]string{
+	"hex":          "0123456789abcdef",
+	"alphanumeric": "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789",
+}
+
+// saltFrom reads a salt of the given length from source. Printable charsets draw each character
+// uniformly, skipping bytes past the largest multiple of the charset size.
+func saltFrom(source io.Reader, length int64, charset types.String) ([]byte, error) {
+	salt := make([]byte, length)
+	chars, ok := saltCharsets[charset.ValueString()]
+	if !ok {
+		_, err := io.ReadFull(source, salt)
+		return salt, err
+	}
+	limit := 256 - 256%len(chars)
+	b := make([]byte, 1)
+	for i := range salt {
+		for {
+			if _, err := io.ReadFull(source, b); err != nil {
+				return nil, err
+			}
+			if int(b[0]) < limit {
+				break
+			}
+		}
+		salt[i] = chars[int(b[0])%len(chars)]
+	}
+	return salt, nil
+}
+
+// newKeySalt generates a random salt as configured by salt_length and salt_charset.
+func newKeySalt(plan KeyResourceData) ([]byte, error) {
+	return saltFrom(rand.Reader, plan.SaltLength.ValueInt64(), plan.SaltCharset)
+}
+
+// saltCharsetChanged reports whether salt_charset differs between plan and state, where keys
+// created before it existed have raw salts.
+func saltCharsetChanged(plan, state KeyResourceData) bool {
+	if state.SaltCharset.IsNull() {
+		return plan.SaltCharset.ValueString() != "bytes"
+	}
+	return !plan.SaltCharset.Equal(state.SaltCharset)
+}
+
+// calibrateIterations measures the local PBKDF2 rate of p and scales it to the target duration.
+func calibrateIterations(target time.Duration, p pbkdf2kit.PRF) int64 {
+	salt := make([]byte, 16)
+	for probe := 1000; ; probe *= 2 {
+		start := time.Now()
+		_, _ = pbkdf2kit.Key(p, []byte("calibration"), salt, probe, p.Size)
+		elapsed := time.Since(start)
+		if elapsed >= 20*time.Millisecond || probe >= 1<<24 {
+			return max(1, int64(float64(probe)*float64(target)/float64(elapsed)))
+		}
+	}
+}
+
+// resolvePepper picks the pepper of a key: its own pepper_wo or pepper, else the provider's.
+func resolvePepper(plan KeyResourceData, provider *pbkdf2ProviderData) string {
+	switch {
+	case !plan.PepperWo.IsNull():
+		return plan.PepperWo.ValueString()
+	case !plan.Pepper.IsNull():
+		return plan.Pepper.ValueString()
+	}
+	return provider.pepper()
+}
+
+// derivedLength is the number of bytes to derive: key_length or the prf output size,
+// or the cipher key and IV together when iv_length is set.
+func derivedLength(plan KeyResourceData) int {
+	size, _ := getHashAlgorithm(plan.Prf.ValueString())
+	if plan.IvLength.IsNull() {
+		if !plan.KeyLength.IsNull() {
+			return int(plan.KeyLength.ValueInt64())
+		}
+		return size
+	}
+	if !plan.CipherKeyLength.IsNull() {
+		size = int(plan.CipherKeyLength.ValueInt64())
+	}
+	return size + int(plan.IvLength.ValueInt64())
+}
+
+func derive(plan KeyResourceData, provider *pbkdf2ProviderData, password string, salt []byte) ([]byte, string, error) {
+	release := provider.acquireDerivation()
+	dk, err := pbkdf2kit.Derive(pbkdf2kit.Params{
+		PRF:        plan.Prf.ValueString(),
+		Iterations: int(plan.Iterations.ValueInt64()),
+		KeyLength:  derivedLength(plan),
+		PreHash:    plan.PreHash.ValueBool(),
+		Pepper:     resolvePepper(plan, provider),
+		PepperMode: plan.PepperMode.ValueString(),
+		Context:    provider.derivationContext(),
+	}, password, salt)
+	release()
+	if err != nil {
+		return nil, "", derivationError{err}
+	}
+	p, _ := pbkdf2kit.LookupPRF(plan.Prf.ValueString())
+	m := pbkdf2kit.Material{
+		Iterations:    int(plan.Iterations.ValueInt64()),
+		Salt:          salt,
+		Key:           dk,
+		HashAlgorithm: p.Alias,
+	}
+	if plan.PasswordWoVersion.IsNull() {
+		// A write-only password must not end up in the result stored in state.
+		m.Password = password
+	}
+	if preset, ok := pbkdf2kit.LookupPreset(plan.FormatPreset.ValueString()); ok {
+		return dk, preset.Format(p, m), nil
+	}
+	result, err := pbkdf2kit.FormatTemplate(plan.Format.ValueString(), m)
+	return dk, result, err
+}
+
+// existingSalt recovers the salt of a hash in one of the layouts pbkdf2kit can parse.
+func existingSalt(hash string) ([]byte, bool) {
+	if parsed, err := pbkdf2kit.ParseSCRAMVerifier(hash); err == nil {
+		return parsed.Salt, true
+	}
+	if salt, _, err := pbkdf2kit.ParseSaltKey(hash); err == nil {
+		return salt, true
+	}
+	if parsed, err := pbkdf2kit.ParseHash(hash); err == nil {
+		return parsed.Salt, true
+	}
+	return nil, false
+}
+
+// adoptedSalt returns the salt of existing_hash when password derives to exactly that hash with the planned parameters.
+func adoptedSalt(plan KeyResourceData, provider *pbkdf2ProviderData) ([]byte, bool) {
+	if plan.ExistingHash.IsNull() || plan.ExistingHash.IsUnknown() {
+		return nil, false
+	}
+	salt, ok := existingSalt(plan.ExistingHash.ValueString())
+	if !ok {
+		return nil, false
+	}
+	_, result, err := derive(plan, provider, plan.Password.ValueString(), salt)
+	if err != nil || subtle.ConstantTimeCompare([]byte(result), []byte(plan.ExistingHash.ValueString())) != 1 {
+		return nil, false
+	}
+	return salt, true
+}
+
+// saltReplaced reports whether any input listed in replace_on changed, which calls for a new salt.
+func saltReplaced(ctx context.Context, plan, state KeyResourceData, diags *diag.Diagnostics) bool {
+	var inputs []string
+	diags.Append(plan.ReplaceOn.ElementsAs(ctx, &inputs, false)...)
+	for _, input := range inputs {
+		if changed, ok := replaceOnChanged[input]; ok && changed(plan, state) {
+			return true
+		}
+	}
+	return false
+}
+
+// derivationError is a failure of the derivation itself rather than of the format template.
+type derivationError struct {
+	error
+}
+
+// templateErrorPattern splits text/template errors into line, column, offending node and message.
+var templateErrorPattern = regexp.MustCompile(`(?s)^template: [^:]*:(\d+)(?::(\d+))?: (?:executing "[^"]*" at <(.*?)>: )?(.*)$`)
+
+// addFormatError reports a template failure against the format attribute, keeping its position.
+// With redact set only the position is kept, since messages from template functions may
+// quote the values they were called with. Failures of the derivation are reported against password.
+func addFormatError(diags *diag.Diagnostics, err error, redact bool) {
+	var derivationErr derivationError
+	if errors.As(err, &derivationErr) {
+		diags.AddAttributeError(path.Root("password"), "Derivation Error", derivationErr.Error())
+		return
+	}
+	detail := err.Error()
+	if m := templateErrorPattern.FindStringSubmatch(detail); m != nil {
+		detail = "Line " + m[1]
+		if m[2] != "" {
+			detail += ", column " + m[2]
+		}
+		if redact {
+			detail += ": the format template failed (details redacted)"
+		} else {
+			if m[3] != "" {
+				detail += ", at <" + m[3] + ">"
+			}
+			detail += ": " + strings.ReplaceAll(m[4], fmt.Sprintf("type %T", pbkdf2kit.Material{}), "the format data")
+		}
+	} else if redact {
+		detail = "The format template failed (details redacted)."
+	}
+	diags.AddAttributeError(path.Root("format"), "Invalid Format Template", detail)
+}
+
+// ValidateConfig dry-runs the format template, so a broken one fails the plan rather than the apply.
+// The placeholder material it renders holds nothing of the key, so errors are never redacted.
+func (r *KeyResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var format types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("format"), &format)...)
+	if resp.Diagnostics.HasError() || format.IsNull() || format.IsUnknown() {
+		return
+	}
+	if err := pbkdf2kit.ValidateFormat(format.ValueString()); err != nil {
+		addFormatError(&resp.Diagnostics, err, false)
+	}
+}
+
+func (r *KeyResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var config KeyResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	keyLen, hashFunc := getHashAlgorithm(plan.HashAlgorithm.ValueString())
+	var state *KeyResourceData
+	if !req.State.Raw.IsNull() {
+		state = &KeyResourceData{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
 
-	var salt = make([]byte, plan.SaltLength.ValueInt64())
-	_, err := rand.Read(salt[:])
+	r.checkPlaceholder(config.Password, path.Root("password"), resp)
+	r.checkPlaceholder(config.PasswordWo, path.Root("password_wo"), resp)
+	r.checkPlaceholder(config.NextPassword, path.Root("next_password"), resp)
+	r.checkPasswordStrength(config.Password, path.Root("password"), resp)
+	r.checkPasswordStrength(config.PasswordWo, path.Root("password_wo"), resp)
+	r.checkPasswordStrength(config.NextPassword, path.Root("next_password"), resp)
+	planFormat(ctx, config, r.provider.defaultFormat(), resp)
+	planPRF(ctx, config, r.provider.defaultPRF(), resp)
+	planIterations(ctx, config, state, r.provider.defaultIterations(), resp)
+	planSaltLength(ctx, config, r.provider.defaultSaltLength(), resp)
+	checkFormatPreset(ctx, config, resp)
+	checkContextFormat(ctx, r.provider, resp)
+	checkRecipients(ctx, config, resp)
+	checkSaltInput(config, resp)
+	checkExpectedPattern(config, resp)
+	if r.provider.fipsMode() {
+		checkFIPS(ctx, resp)
+	}
+	if state == nil {
+		checkExistingHash(ctx, r.provider, resp)
+	}
+	checkImportedKey(ctx, state, r.provider, resp)
+	checkImportedSalt(ctx, config, state, resp)
+	planLostKey(ctx, state, resp)
+	checkPlanCost(ctx, r.provider.planCost(), resp)
+	planKnownResult(ctx, r.provider, resp)
+}
+
+// planKnownResult derives the key during plan when its salt comes from salt_input or salt_seed and every input is known,
+// so reviewers see exactly what will be written downstream instead of (known after apply). Such a derivation
+// is deterministic, so the apply reproduces the planned values.
+func planKnownResult(ctx context.Context, provider *pbkdf2ProviderData, resp *resource.ModifyPlanResponse) {
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.Result.IsUnknown() || (plan.SaltSeed.IsNull() && plan.SaltInput.IsNull()) {
+		return
+	}
+	if !plan.Recipients.IsNull() || !plan.PepperWoVersion.IsNull() || !plan.PasswordWoVersion.IsNull() {
+		// Encryption is randomized, and write-only values are only available during apply.
+		return
+	}
+	for _, value := range []attr.Value{plan.SaltSeed, plan.SaltInput, plan.SaltInputEncoding, plan.Password, plan.Iterations, plan.Prf, plan.Format, plan.FormatPreset,
+		plan.SaltLength, plan.SaltCharset, plan.PreHash, plan.Pepper, plan.PepperMode, plan.KeyLength, plan.CipherKeyLength, plan.IvLength, plan.SQLRole, plan.SQLDialect} {
+		if value.IsUnknown() {
+			return
+		}
+	}
+
+	var salt []byte
+	var err error
+	if !plan.SaltInput.IsNull() {
+		salt, err = decodeSaltInput(plan)
+	} else {
+		salt, err = seededSalt(plan.SaltSeed.ValueString(), plan.SaltLength.ValueInt64(), plan.SaltCharset)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("Salt Error", err.Error())
 		return
 	}
-	dk := pbkdf2.Key([]byte(plan.Password.ValueString()), salt, int(plan.Iterations.ValueInt64()), keyLen, hashFunc)
-	var key bytes.Buffer
-	formatTemplate := template.New("format")
-	formatTemplate.Funcs(template.FuncMap{
-		"bin":    bin,
-		"b64enc": b64enc,
-	})
-	_, err = formatTemplate.Parse(plan.Format.ValueString())
+	dk, result, err := derive(plan, provider, plan.Password.ValueString(), salt)
 	if err != nil {
-		resp.Diagnostics.AddError("Format Error", err.Error())
+		// The apply reports the failure.
 		return
 	}
-	err = formatTemplate.Execute(&key, toFmt{
-		Iterations: int(plan.Iterations.ValueInt64()),
-		Salt:       salt,
-		Key:        dk,
-	})
+	cipherKey, iv, kcv, sqlStatement := keyOutputs(plan, dk, result)
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("salt"), base64.StdEncoding.EncodeToString(salt))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("key"), base64.StdEncoding.EncodeToString(dk))...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("cipher_key"), cipherKey)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("iv"), iv)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("kcv"), kcv)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("result"), result)...)
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("sql_statement"), sqlStatement)...)
+}
+
+// checkExistingHash warns when existing_hash will not be adopted, since the key then gets a new salt
+// and every consumer of the hash has to be updated.
+func checkExistingHash(ctx context.Context, provider *pbkdf2ProviderData, resp *resource.ModifyPlanResponse) {
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.ExistingHash.IsNull() || plan.ExistingHash.IsUnknown() ||
+		plan.Password.IsUnknown() || plan.Iterations.IsUnknown() || plan.Prf.IsUnknown() || plan.Format.IsUnknown() {
+		return
+	}
+	if !plan.PepperWoVersion.IsNull() || !plan.PasswordWoVersion.IsNull() {
+		// Write-only values are only available during apply.
+		return
+	}
+
+	if _, ok := existingSalt(plan.ExistingHash.ValueString()); !ok {
+		resp.Diagnostics.AddAttributeWarning(path.Root("existing_hash"), "Existing Hash Not Adopted",
+			"The salt can't be recovered from existing_hash, as it is in none of the supported layouts, so a new salt will be generated.")
+		return
+	}
+	if _, ok := adoptedSalt(plan, provider); !ok {
+		resp.Diagnostics.AddAttributeWarning(path.Root("existing_hash"), "Existing Hash Not Adopted",
+			"The password doesn't derive to existing_hash with the configured prf, iterations, format and pepper, "+
+				"so the password or parameters changed and a new salt will be generated. Consumers of the existing hash have to be updated.")
+	}
+}
+
+// checkPlanCost adds the estimated time of the derivations this plan will run to the provider's total,
+// and warns once when the total crosses plan_cost_warning_ms.
+func checkPlanCost(ctx context.Context, cost *planCost, resp *resource.ModifyPlanResponse) {
+	if cost == nil || resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.Result.IsUnknown() {
+		// Nothing is derived when result is carried over from state.
+		return
+	}
+
+	derivations := 1 + len(plan.OldPasswords.Elements())
+	if !plan.NextPassword.IsNull() {
+		derivations++
+	}
+	var estimate time.Duration
+	switch {
+	case !plan.Iterations.IsUnknown():
+		estimate = cost.estimate(plan.Prf.ValueString(), plan.Iterations.ValueInt64(), derivations)
+	case !plan.TargetDurationMs.IsNull() && !plan.TargetDurationMs.IsUnknown():
+		// Calibrated during apply, so each derivation takes about the target.
+		estimate = time.Duration(plan.TargetDurationMs.ValueInt64()) * time.Millisecond * time.Duration(derivations)
+	}
+
+	if total, exceeded := cost.add(estimate); exceeded {
+		resp.Diagnostics.AddWarning("Expensive Apply",
+			fmt.Sprintf("The pbkdf2_key resources planned so far are estimated to take %s of CPU time on this host to derive, "+
+				"more than plan_cost_warning_ms in the provider configuration. Expect the apply to take at least that long.",
+				total.Round(time.Millisecond)))
+	}
+}
+
+// checkFIPS rejects parameters below the minimums of NIST SP 800-132.
+func checkFIPS(ctx context.Context, resp *resource.ModifyPlanResponse) {
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.SaltLength.IsUnknown() && plan.SaltLength.ValueInt64() < 16 {
+		resp.Diagnostics.AddAttributeError(path.Root("salt_length"), "FIPS Mode",
+			fmt.Sprintf("SP 800-132 requires a salt of at least 16 bytes, got %d.", plan.SaltLength.ValueInt64()))
+	}
+	if p, ok := pbkdf2kit.LookupPRF(plan.Prf.ValueString()); ok && !p.Approved {
+		resp.Diagnostics.AddAttributeError(path.Root("prf"), "FIPS Mode",
+			fmt.Sprintf("SP 800-132 requires an HMAC PRF with an approved hash, got %q.", p.Name))
+	}
+	if !plan.Iterations.IsUnknown() && plan.Iterations.ValueInt64() < 1000 {
+		resp.Diagnostics.AddAttributeError(path.Root("iterations"), "FIPS Mode",
+			fmt.Sprintf("SP 800-132 requires at least 1000 iterations, got %d.", plan.Iterations.ValueInt64()))
+	}
+	if !plan.Prf.IsUnknown() && !plan.KeyLength.IsUnknown() && !plan.CipherKeyLength.IsUnknown() && !plan.IvLength.IsUnknown() {
+		keyLen := derivedLength(plan)
+		attr := path.Root("key_length")
+		if !plan.IvLength.IsNull() {
+			keyLen -= int(plan.IvLength.ValueInt64())
+			attr = path.Root("cipher_key_length")
+		}
+		if keyLen < 14 {
+			resp.Diagnostics.AddAttributeError(attr, "FIPS Mode",
+				fmt.Sprintf("SP 800-132 requires keys of at least 112 bits, got %d bytes.", keyLen))
+		}
+	}
+}
+
+// checkContextFormat rejects format_preset and custom formats under the provider's derivation_context.
+func checkContextFormat(ctx context.Context, provider *pbkdf2ProviderData, resp *resource.ModifyPlanResponse) {
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if attr, conflict := contextFormatConflict(plan, provider); conflict {
+		resp.Diagnostics.AddAttributeError(attr, "Derivation Context Conflict", contextFormatDetail)
+	}
+}
+
+// contextFormatDetail explains why derivation_context only goes with the default format.
+const contextFormatDetail = "The provider's derivation_context is mixed into the salt but not recorded in result, so a hash rendered by format_preset " +
+	"or a custom format couldn't be verified by the system it is meant for. Keep the default format, or unset derivation_context."
+
+// contextFormatConflict reports the attribute that renders a result its consumer can't verify under the provider's
+// derivation_context. Only the default `<b64 salt>:<b64 key>` result goes with a context, as it is read back by this provider.
+func contextFormatConflict(data KeyResourceData, provider *pbkdf2ProviderData) (path.Path, bool) {
+	if provider.derivationContext() == "" {
+		return path.Path{}, false
+	}
+	if data.FormatPreset.ValueString() != "" {
+		return path.Root("format_preset"), true
+	}
+	if !data.Format.IsNull() && !data.Format.IsUnknown() && data.Format.ValueString() != pbkdf2kit.DefaultFormat {
+		return path.Root("format"), true
+	}
+	return path.Path{}, false
+}
+
+// checkFormatPreset rejects parameters the consumer of the format preset doesn't support.
+func checkFormatPreset(ctx context.Context, config KeyResourceData, resp *resource.ModifyPlanResponse) {
+	preset, ok := pbkdf2kit.LookupPreset(config.FormatPreset.ValueString())
+	if !ok {
+		return
+	}
+
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || plan.Prf.IsUnknown() {
+		return
+	}
+	params := pbkdf2kit.ParameterSet{
+		PRF:        plan.Prf.ValueString(),
+		Iterations: plan.Iterations.ValueInt64(),
+		SaltLength: int(plan.SaltLength.ValueInt64()),
+		KeyLength:  derivedLength(plan),
+		RawSalt:    plan.SaltInput.IsNull() && plan.SaltCharset.ValueString() == "bytes",
+	}
+	for _, violation := range preset.Violations(params) {
+		resp.Diagnostics.AddAttributeError(path.Root("format_preset"), "Incompatible Format Preset",
+			fmt.Sprintf("The %s format preset %s.", preset.Name, violation))
+	}
+}
+
+// checkRecipients rejects recipients that can't be parsed, and share thresholds above their count, during plan rather than apply.
+func checkRecipients(ctx context.Context, config KeyResourceData, resp *resource.ModifyPlanResponse) {
+	if config.Recipients.IsNull() || config.Recipients.IsUnknown() {
+		return
+	}
+
+	var recipients []types.String
+	resp.Diagnostics.Append(config.Recipients.ElementsAs(ctx, &recipients, false)...)
+	for i, recipient := range recipients {
+		if recipient.IsNull() || recipient.IsUnknown() {
+			continue
+		}
+		if _, err := parseRecipient(recipient.ValueString()); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("recipients").AtListIndex(i), "Invalid Recipient", err.Error())
+		}
+	}
+	if !config.ShareThreshold.IsNull() && !config.ShareThreshold.IsUnknown() && config.ShareThreshold.ValueInt64() > int64(len(recipients)) {
+		resp.Diagnostics.AddAttributeError(path.Root("share_threshold"), "Invalid Share Threshold",
+			fmt.Sprintf("The key is split into one share per recipient, so a threshold of %d needs at least %d recipients, got %d.",
+				config.ShareThreshold.ValueInt64(), config.ShareThreshold.ValueInt64(), len(recipients)))
+	}
+}
+
+// checkSaltInput rejects a salt_input that doesn't decode during plan rather than apply.
+func checkSaltInput(config KeyResourceData, resp *resource.ModifyPlanResponse) {
+	if config.SaltInput.IsNull() || config.SaltInput.IsUnknown() || config.SaltInputEncoding.IsUnknown() {
+		return
+	}
+	if _, err := decodeSaltInput(config); err != nil {
+		// The salt may come from a hash that is still in use, so it is not quoted.
+		resp.Diagnostics.AddAttributeError(path.Root("salt_input"), "Invalid Salt Input", "salt_input is not encoded as salt_input_encoding says.")
+	}
+}
+
+// checkExpectedPattern rejects an expected_pattern that doesn't compile during plan rather than apply.
+func checkExpectedPattern(config KeyResourceData, resp *resource.ModifyPlanResponse) {
+	if config.ExpectedPattern.IsNull() || config.ExpectedPattern.IsUnknown() {
+		return
+	}
+	if _, err := regexp.Compile(config.ExpectedPattern.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("expected_pattern"), "Invalid Expected Pattern", err.Error())
+	}
+}
+
+// planFormat plans the contents of format_file as format, or the provider's default_format when neither is set.
+func planFormat(ctx context.Context, config KeyResourceData, defaultFormat string, resp *resource.ModifyPlanResponse) {
+	if config.FormatFile.IsNull() {
+		if config.Format.IsNull() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("format"), defaultFormat)...)
+		}
+		return
+	}
+	if config.FormatFile.IsUnknown() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("format"), types.StringUnknown())...)
+		return
+	}
+
+	format, err := os.ReadFile(config.FormatFile.ValueString())
 	if err != nil {
-		resp.Diagnostics.AddError("Format Error", err.Error())
+		resp.Diagnostics.AddAttributeError(path.Root("format_file"), "Invalid Format File", err.Error())
 		return
 	}
-	saltStr := string(salt)
-	keyStr := string(dk)
-	result := key.String()
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("iterations"), plan.Iterations)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("format"), plan.Format)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("password"), plan.Password)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("hash_algorithm"), plan.HashAlgorithm)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("salt_length"), plan.SaltLength)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("salt"), saltStr)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("key"), keyStr)...)
-	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("result"), result)...)
+	// Unlike format, the template of format_file is only known here, so it is dry-run here.
+	if err := pbkdf2kit.ValidateFormat(string(format)); err != nil {
+		addFormatError(&resp.Diagnostics, err, false)
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("format"), string(format))...)
+}
+
+// planPRF resolves prf from either attribute, or the provider's default_prf, and mirrors its legacy alias into hash_algorithm.
+func planPRF(ctx context.Context, config KeyResourceData, defaultPRF string, resp *resource.ModifyPlanResponse) {
+	if config.Prf.IsUnknown() || config.HashAlgorithm.IsUnknown() {
+		return
+	}
+
+	name := defaultPRF
+	if !config.Prf.IsNull() {
+		name = config.Prf.ValueString()
+	} else if !config.HashAlgorithm.IsNull() {
+		name = config.HashAlgorithm.ValueString()
+	}
+	p, ok := pbkdf2kit.LookupPRF(name)
+	if !ok {
+		p, _ = pbkdf2kit.LookupPRF(pbkdf2kit.DefaultPRF)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("prf"), p.Name)...)
+	if config.HashAlgorithm.IsNull() {
+		alias := types.StringValue(p.Alias)
+		if p.Alias == "" {
+			// PRFs added since hash_algorithm was deprecated have no bare hash name.
+			alias = types.StringNull()
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("hash_algorithm"), alias)...)
+	}
+}
+
+// checkPlaceholder rejects passwords that are known placeholders or match the configured placeholder pattern.
+func (r *KeyResource) checkPlaceholder(password types.String, attrPath path.Path, resp *resource.ModifyPlanResponse) {
+	if r.provider == nil || password.IsNull() || password.IsUnknown() {
+		return
+	}
+
+	value := strings.TrimSpace(password.ValueString())
+	placeholder := r.provider.PlaceholderPasswordPattern != nil && r.provider.PlaceholderPasswordPattern.MatchString(value)
+	for _, p := range r.provider.PlaceholderPasswords {
+		placeholder = placeholder || strings.EqualFold(value, p)
+	}
+	if !placeholder {
+		return
+	}
+	if r.provider.RejectPlaceholderPasswords {
+		resp.Diagnostics.AddAttributeError(attrPath, "Placeholder Password",
+			"The password is a placeholder that must not be hashed and deployed. Supply the real password, "+
+				"or adjust placeholder_passwords and placeholder_password_pattern in the provider configuration.")
+		return
+	}
+	resp.Diagnostics.AddAttributeWarning(attrPath, "Placeholder Password",
+		"The password looks like a placeholder that should not be hashed and deployed. Supply the real password, "+
+			"or adjust placeholder_passwords and placeholder_password_pattern in the provider configuration. "+
+			"Set reject_placeholder_passwords to fail the plan instead.")
+}
+
+// checkPasswordStrength warns about passwords scoring below the configured zxcvbn threshold.
+func (r *KeyResource) checkPasswordStrength(password types.String, attrPath path.Path, resp *resource.ModifyPlanResponse) {
+	if r.provider == nil || r.provider.MinPasswordScore <= 0 || password.IsNull() || password.IsUnknown() {
+		return
+	}
+
+	strength := zxcvbn.PasswordStrength(password.ValueString(), nil)
+	if strength.Score < r.provider.MinPasswordScore {
+		resp.Diagnostics.AddAttributeWarning(attrPath, "Weak Password",
+			fmt.Sprintf("The password is easily guessable (strength score %d of 4, minimum %d). "+
+				"Use a longer, less predictable password, or lower min_password_score in the provider configuration.",
+				strength.Score, r.provider.MinPasswordScore))
+	}
+}
+
+// planSaltLength plans the provider's default_salt_length for keys that don't set salt_length.
+func planSaltLength(ctx context.Context, config KeyResourceData, defaultSaltLength int64, resp *resource.ModifyPlanResponse) {
+	if config.SaltLength.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("salt_length"), defaultSaltLength)...)
+	}
+}
+
+// planIterations pins iterations from security_level or calibrated from target_duration_ms, marks them unknown
+// when calibration is due, or falls back to the provider default when none of them is configured.
+func planIterations(ctx context.Context, config KeyResourceData, state *KeyResourceData, defaultIterations int64, resp *resource.ModifyPlanResponse) {
+	if !config.Iterations.IsNull() {
+		return
+	}
+	if !config.SecurityLevel.IsNull() {
+		var name types.String
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("prf"), &name)...)
+		if name.IsUnknown() || config.SecurityLevel.IsUnknown() {
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("iterations"), types.Int64Unknown())...)
+			return
+		}
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("iterations"), securityLevels[config.SecurityLevel.ValueString()][name.ValueString()])...)
+		return
+	}
+	if config.TargetDurationMs.IsNull() {
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("iterations"), defaultIterations)...)
+		return
+	}
+
+	if state == nil || !config.TargetDurationMs.Equal(state.TargetDurationMs) {
+		// Calibrated during apply.
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("iterations"), types.Int64Unknown())...)
+		return
+	}
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("iterations"), state.Iterations)...)
 }
 
 func (r KeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
-	generate(ctx, KeyRequest{Plan: &req.Plan}, &KeyResponse{State: &resp.State, Diagnostics: &resp.Diagnostics})
+	generate(ctx, KeyRequest{Config: &req.Config, Plan: &req.Plan, Provider: r.provider}, &KeyResponse{State: &resp.State, Diagnostics: &resp.Diagnostics})
 }
 
 func (r KeyResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
-	// Not needed
+	var state KeyResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Nothing is stored remotely, so refreshing can only confirm that the stored
+	// material still matches what the stored inputs derive to. That costs a full
+	// derivation, so it is opt-in. Write-only passwords and peppers are not
+	// available here and encrypted keys are not stored in the clear, so none of
+	// them can be compared.
+	if r.provider.verifyOnRefresh() && state.PepperWoVersion.IsNull() && state.Recipients.IsNull() &&
+		(!consistent(state, r.provider, state.Password, state.Salt, state.Key, state.Result) ||
+			!consistent(state, r.provider, state.NextPassword, state.NextSalt, state.NextKey, state.NextResult)) {
+		if state.DeletionProtection.ValueBool() {
+			resp.Diagnostics.AddError("Inconsistent State",
+				"The stored salt, key or result of this pbkdf2_key no longer match its inputs, so the state was edited by hand or is corrupted, "+
+					"or the pepper or provider derivation_context changed. It has deletion_protection enabled, so it is not replaced on its own: "+
+					"restore the previous inputs, or set force_destroy = true and replace it.")
+			return
+		}
+		resp.Diagnostics.AddWarning("Inconsistent State",
+			"The stored salt, key or result of this pbkdf2_key no longer match its inputs, so the state was edited by hand or is corrupted, "+
+				"or the pepper or provider derivation_context changed. It was removed from state, so the next apply derives fresh values.")
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	if r.provider != nil && r.provider.IntegrityKey != "" && !state.IntegrityTag.IsNull() &&
+		!hmac.Equal([]byte(integrityTag(r.provider.IntegrityKey, state)), []byte(state.IntegrityTag.ValueString())) {
+		resp.Diagnostics.AddError("State Integrity Check Failed",
+			"The integrity_tag of this pbkdf2_key does not match its stored salt, key and parameters under the "+
+				"configured integrity_key, so the state was tampered with or is corrupted, or integrity_key was changed. "+
+				"Replace the resource to derive fresh values.")
+	}
+	checkDuplicates(r.provider.seenMaterial(), &resp.Diagnostics, state.Salt, state.Key, state.NextSalt, state.NextKey)
+}
+
+// stateBytes decodes a base64 encoded salt or key of the state. A null value decodes to no bytes.
+func stateBytes(value types.String) []byte {
+	b, _ := base64.StdEncoding.DecodeString(value.ValueString())
+	return b
+}
+
+// integrityTag computes a hex HMAC-SHA256 over the stored material and the parameters it was derived with.
+// Every field is length prefixed so values can't be shifted between fields. The material is tagged decoded,
+// so tags written while it was stored as raw bytes still verify after the state upgrade.
+func integrityTag(secret string, data KeyResourceData) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, field := range []string{
+		fmt.Sprint(data.Iterations.ValueInt64()),
+		data.Prf.ValueString(),
+		data.Format.ValueString(),
+		string(stateBytes(data.Salt)),
+		string(stateBytes(data.Key)),
+		string(stateBytes(data.NextSalt)),
+		string(stateBytes(data.NextKey)),
+	} {
+		mac.Write([]byte(bin(8, len(field))))
+		mac.Write([]byte(field))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// consistent re-derives key and result from password and salt and compares them with the stored values.
+func consistent(state KeyResourceData, provider *pbkdf2ProviderData, password, salt, key, result types.String) bool {
+	if password.IsNull() || salt.IsNull() {
+		return true
+	}
+	dk, formatted, err := derive(state, provider, password.ValueString(), stateBytes(salt))
+	if err != nil {
+		return false
+	}
+	return base64.StdEncoding.EncodeToString(dk) == key.ValueString() && formatted == result.ValueString()
 }
 
 func (r KeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
-	generate(ctx, KeyRequest{Plan: &req.Plan}, &KeyResponse{State: &resp.State, Diagnostics: &resp.Diagnostics})
+	generate(ctx, KeyRequest{Config: &req.Config, Plan: &req.Plan, State: &req.State, Provider: r.provider}, &KeyResponse{State: &resp.State, Diagnostics: &resp.Diagnostics})
 }
 
 func (r KeyResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state KeyResourceData
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DeletionProtection.ValueBool() && !state.ForceDestroy.ValueBool() {
+		resp.Diagnostics.AddError("Deletion Protection",
+			"This pbkdf2_key has deletion_protection enabled, and data protected by it can't be recovered once "+
+				"the key is destroyed. Set force_destroy = true and apply before destroying it.")
+		return
+	}
+
 	resp.State.RemoveResource(ctx)
 }
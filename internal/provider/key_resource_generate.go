@@ -0,0 +1,383 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// generate derives the key material of a create or update and writes the resulting state in one go.
+// Each optional feature fills in its own outputs, so an unused one is left null.
+func generate(ctx context.Context, req KeyRequest, resp *KeyResponse) {
+	var plan KeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var state *KeyResourceData
+	if req.State != nil {
+		state = &KeyResourceData{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Write-only values are only ever in the configuration.
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("pepper_wo"), &plan.PepperWo)...)
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("password_wo"), &plan.PasswordWo)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// The key derives from password_wo in place of password, which stays null in state.
+	statePassword := plan.Password
+	if !plan.PasswordWo.IsNull() {
+		plan.Password = plan.PasswordWo
+	}
+
+	if plan.Iterations.IsUnknown() && !plan.SecurityLevel.IsNull() {
+		plan.Iterations = types.Int64Value(securityLevels[plan.SecurityLevel.ValueString()][plan.Prf.ValueString()])
+	} else if plan.Iterations.IsUnknown() {
+		target := time.Duration(plan.TargetDurationMs.ValueInt64()) * time.Millisecond
+		plan.Iterations = types.Int64Value(max(calibrateIterations(target, lookupPRF(plan.Prf.ValueString())), req.Provider.minIterations()))
+	}
+
+	salt, promote := keySalt(ctx, plan, state, req.Provider, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	dk, result, err := derive(plan, req.Provider, plan.Password.ValueString(), salt)
+	if err != nil {
+		addFormatError(resp.Diagnostics, err, req.Provider.redactErrors())
+		return
+	}
+
+	plan.NextSalt, plan.NextKey, plan.NextResult = nextMaterial(plan, state, promote, req.Provider, resp.Diagnostics)
+	oldResults, oldSalts := oldMaterial(ctx, plan, state, req.Provider, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	checkExpectedResults(plan, append([]string{result}, oldResults...), resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var diags diag.Diagnostics
+	plan.OldResults, diags = types.ListValueFrom(ctx, types.StringType, oldResults)
+	resp.Diagnostics.Append(diags...)
+	plan.OldSalts, diags = types.ListValueFrom(ctx, types.StringType, oldSalts)
+	resp.Diagnostics.Append(diags...)
+	plan.Attestation = keyAttestation(ctx, plan, req.Provider, len(salt), len(dk), keyCreatedAt(state, salt, dk), resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Salt = types.StringValue(base64.StdEncoding.EncodeToString(salt))
+	plan.Key = types.StringValue(base64.StdEncoding.EncodeToString(dk))
+	plan.Result = types.StringValue(result)
+	plan.CipherKey, plan.Iv, plan.Kcv, plan.SQLStatement = keyOutputs(plan, dk, result)
+	checkDuplicates(req.Provider.seenMaterial(), resp.Diagnostics, plan.Salt, plan.Key, plan.NextSalt, plan.NextKey)
+
+	encryptMaterial(ctx, &plan, dk, result, resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.IntegrityTag = types.StringNull()
+	if req.Provider != nil && req.Provider.IntegrityKey != "" {
+		plan.IntegrityTag = types.StringValue(integrityTag(req.Provider.IntegrityKey, plan))
+	}
+
+	plan.Password = statePassword
+	plan.PasswordWo = types.StringNull()
+	plan.PepperWo = types.StringNull()
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+// keySalt picks the salt of the key and reports whether the next key is being promoted.
+func keySalt(ctx context.Context, plan KeyResourceData, state *KeyResourceData, provider *pbkdf2ProviderData, diags *diag.Diagnostics) ([]byte, bool) {
+	var adopted []byte
+	if state == nil {
+		adopted, _ = adoptedSalt(plan, provider)
+	}
+	promote := state != nil && plan.Promotions.ValueInt64() > state.Promotions.ValueInt64()
+
+	switch {
+	case promote:
+		if state.NextPassword.IsNull() || state.NextSalt.IsNull() {
+			diags.AddAttributeError(path.Root("promotions"), "Promotion Error",
+				"There is no next key to promote, set next_password and apply before promoting.")
+			return nil, true
+		}
+		if !plan.Password.Equal(state.NextPassword) {
+			diags.AddAttributeError(path.Root("password"), "Promotion Error",
+				"The password must be set to the previous next_password when promoting.")
+			return nil, true
+		}
+		// The promoted key must stay byte for byte what consumers already accept as next.
+		return stateBytes(state.NextSalt), true
+	case !plan.SaltInput.IsNull():
+		salt, err := decodeSaltInput(plan)
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt_input"), "Invalid Salt Input", "salt_input is not encoded as salt_input_encoding says.")
+		}
+		return salt, false
+	case !plan.SaltSeed.IsNull():
+		salt, err := seededSalt(plan.SaltSeed.ValueString(), plan.SaltLength.ValueInt64(), plan.SaltCharset)
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt_seed"), "Salt Error", err.Error())
+		}
+		return salt, false
+	case adopted != nil:
+		// The hash already deployed is kept, so nothing has to be rotated.
+		return adopted, false
+	case imported(state):
+		// The first apply after import records the password and keeps the imported salt.
+		return stateBytes(state.Salt), false
+	case state != nil && !state.Salt.IsNull() && plan.SaltLength.Equal(state.SaltLength) && !saltCharsetChanged(plan, *state) && !saltReplaced(ctx, plan, *state, diags):
+		// None of the replace_on inputs changed, so the key is re-derived in place.
+		return stateBytes(state.Salt), false
+	}
+
+	salt, err := newKeySalt(plan)
+	if err != nil {
+		diags.AddError("Salt Error", err.Error())
+	}
+	return salt, false
+}
+
+// nextMaterial derives the salt, key and result of next_password, keeping the stored next salt until the next key is promoted.
+func nextMaterial(plan KeyResourceData, state *KeyResourceData, promote bool, provider *pbkdf2ProviderData, diags *diag.Diagnostics) (types.String, types.String, types.String) {
+	if plan.NextPassword.IsNull() {
+		return types.StringNull(), types.StringNull(), types.StringNull()
+	}
+
+	var salt []byte
+	if state != nil && !promote && plan.NextPassword.Equal(state.NextPassword) && !state.NextSalt.IsNull() {
+		salt = stateBytes(state.NextSalt)
+	} else {
+		var err error
+		if salt, err = newKeySalt(plan); err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return types.StringNull(), types.StringNull(), types.StringNull()
+		}
+	}
+	dk, result, err := derive(plan, provider, plan.NextPassword.ValueString(), salt)
+	if err != nil {
+		addFormatError(diags, err, provider.redactErrors())
+		return types.StringNull(), types.StringNull(), types.StringNull()
+	}
+	return types.StringValue(base64.StdEncoding.EncodeToString(salt)), types.StringValue(base64.StdEncoding.EncodeToString(dk)), types.StringValue(result)
+}
+
+// oldMaterial hashes each of old_passwords and returns the results and base64 encoded salts, in the same order.
+func oldMaterial(ctx context.Context, plan KeyResourceData, state *KeyResourceData, provider *pbkdf2ProviderData, diags *diag.Diagnostics) ([]string, []string) {
+	var oldPasswords []string
+	if !plan.OldPasswords.IsNull() {
+		diags.Append(plan.OldPasswords.ElementsAs(ctx, &oldPasswords, false)...)
+	}
+	priorSalts := oldSaltsByPassword(ctx, state, diags)
+	if diags.HasError() {
+		return nil, nil
+	}
+
+	results := make([]string, 0, len(oldPasswords))
+	salts := make([]string, 0, len(oldPasswords))
+	for _, oldPassword := range oldPasswords {
+		// Each previous password gets its own salt so history entries can't be correlated.
+		salt, ok := priorSalts[oldPassword]
+		if !ok {
+			var err error
+			if salt, err = newKeySalt(plan); err != nil {
+				diags.AddError("Salt Error", err.Error())
+				return nil, nil
+			}
+		}
+		_, result, err := derive(plan, provider, oldPassword, salt)
+		if err != nil {
+			addFormatError(diags, err, provider.redactErrors())
+			return nil, nil
+		}
+		results = append(results, result)
+		salts = append(salts, base64.StdEncoding.EncodeToString(salt))
+	}
+	return results, salts
+}
+
+// oldSaltsByPassword maps each old password in state to the salt it was hashed with.
+// State written before old_salts existed has none, so its entries get new salts once.
+func oldSaltsByPassword(ctx context.Context, state *KeyResourceData, diags *diag.Diagnostics) map[string][]byte {
+	salts := map[string][]byte{}
+	if state == nil || state.OldPasswords.IsNull() || state.OldSalts.IsNull() {
+		return salts
+	}
+	var passwords, encoded []string
+	diags.Append(state.OldPasswords.ElementsAs(ctx, &passwords, false)...)
+	diags.Append(state.OldSalts.ElementsAs(ctx, &encoded, false)...)
+	for i, password := range passwords {
+		if i < len(encoded) {
+			salts[password] = stateBytes(types.StringValue(encoded[i]))
+		}
+	}
+	return salts
+}
+
+// checkExpectedResults matches results and the next result of plan against expected_pattern.
+func checkExpectedResults(plan KeyResourceData, results []string, diags *diag.Diagnostics) {
+	if plan.ExpectedPattern.IsNull() {
+		return
+	}
+	pattern, err := regexp.Compile(plan.ExpectedPattern.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("expected_pattern"), "Invalid Expected Pattern", err.Error())
+		return
+	}
+	if !plan.NextResult.IsNull() {
+		results = append(results, plan.NextResult.ValueString())
+	}
+	for _, r := range results {
+		if !pattern.MatchString(r) {
+			// The result holds key material, so it is not quoted.
+			diags.AddAttributeError(path.Root("expected_pattern"), "Unexpected Result",
+				"A formatted result doesn't match expected_pattern, so the format template likely produces malformed values.")
+			return
+		}
+	}
+}
+
+// keyCreatedAt is the creation time of the key with salt and dk: that in the attestation of state when the update keeps
+// its salt and key, or now. Keys whose key is encrypted to recipients are compared by salt alone.
+func keyCreatedAt(state *KeyResourceData, salt, dk []byte) string {
+	now := time.Now().UTC().Format(time.RFC3339)
+	if state == nil || state.Attestation.IsNull() || state.Salt.ValueString() != base64.StdEncoding.EncodeToString(salt) {
+		return now
+	}
+	if !state.Key.IsNull() && state.Key.ValueString() != base64.StdEncoding.EncodeToString(dk) {
+		return now
+	}
+	var prior attestation
+	if err := json.Unmarshal([]byte(state.Attestation.ValueString()), &prior); err != nil || prior.CreatedAt == "" {
+		return now
+	}
+	return prior.CreatedAt
+}
+
+// keyAttestation renders the attestation of a key with the given salt and key lengths, created at createdAt.
+func keyAttestation(ctx context.Context, plan KeyResourceData, provider *pbkdf2ProviderData, saltLength, keyLength int, createdAt string, diags *diag.Diagnostics) types.String {
+	var tags map[string]string
+	if !plan.Tags.IsNull() {
+		diags.Append(plan.Tags.ElementsAs(ctx, &tags, false)...)
+		if diags.HasError() {
+			return types.StringNull()
+		}
+	}
+
+	version := ""
+	if provider != nil {
+		version = provider.Version
+	}
+	attestationJSON, err := json.Marshal(attestation{
+		Algorithm:       "pbkdf2",
+		PRF:             plan.Prf.ValueString(),
+		Iterations:      plan.Iterations.ValueInt64(),
+		SaltLength:      int64(saltLength),
+		KeyLength:       keyLength,
+		ProviderVersion: version,
+		CreatedAt:       createdAt,
+		Description:     plan.Description.ValueString(),
+		Tags:            tags,
+	})
+	if err != nil {
+		diags.AddError("Attestation Error", err.Error())
+		return types.StringNull()
+	}
+	return types.StringValue(string(attestationJSON))
+}
+
+// encryptMaterial encrypts the key and result to recipients, or splits the key into shares when share_threshold is set,
+// and clears key and result so only the ciphertexts are written to state.
+func encryptMaterial(ctx context.Context, plan *KeyResourceData, dk []byte, result string, diags *diag.Diagnostics) {
+	plan.EncryptedKey = types.StringNull()
+	plan.EncryptedResult = types.StringNull()
+	plan.EncryptedShares = types.ListNull(types.StringType)
+	if plan.Recipients.IsNull() {
+		return
+	}
+
+	var recipients []string
+	diags.Append(plan.Recipients.ElementsAs(ctx, &recipients, false)...)
+	if diags.HasError() {
+		return
+	}
+	parsed, err := parseRecipients(recipients)
+	if err != nil {
+		diags.AddAttributeError(path.Root("recipients"), "Invalid Recipient", err.Error())
+		return
+	}
+	plan.Key = types.StringNull()
+	plan.Result = types.StringNull()
+
+	if plan.ShareThreshold.IsNull() {
+		ciphertextKey, err := encryptFor(parsed, dk)
+		if err != nil {
+			diags.AddError("Encryption Error", err.Error())
+			return
+		}
+		ciphertextResult, err := encryptFor(parsed, []byte(result))
+		if err != nil {
+			diags.AddError("Encryption Error", err.Error())
+			return
+		}
+		plan.EncryptedKey = types.StringValue(ciphertextKey)
+		plan.EncryptedResult = types.StringValue(ciphertextResult)
+		return
+	}
+
+	shares, err := shamirSplit(dk, len(parsed), int(plan.ShareThreshold.ValueInt64()))
+	if err != nil {
+		diags.AddAttributeError(path.Root("share_threshold"), "Invalid Share Threshold", err.Error())
+		return
+	}
+	ciphertexts := make([]string, len(shares))
+	for i, share := range shares {
+		if ciphertexts[i], err = encryptFor(parsed[i:i+1], share); err != nil {
+			diags.AddError("Encryption Error", err.Error())
+			return
+		}
+	}
+	var listDiags diag.Diagnostics
+	plan.EncryptedShares, listDiags = types.ListValueFrom(ctx, types.StringType, ciphertexts)
+	diags.Append(listDiags...)
+}
+
+// keyOutputs splits dk into cipher_key and iv and computes the other outputs of the key and result:
+// kcv and sql_statement.
+func keyOutputs(plan KeyResourceData, dk []byte, result string) (types.String, types.String, types.String, types.String) {
+	cipherKey := types.StringNull()
+	iv := types.StringNull()
+	kcvKey := dk
+	if !plan.IvLength.IsNull() {
+		split := len(dk) - int(plan.IvLength.ValueInt64())
+		cipherKey = types.StringValue(base64.StdEncoding.EncodeToString(dk[:split]))
+		iv = types.StringValue(base64.StdEncoding.EncodeToString(dk[split:]))
+		kcvKey = dk[:split]
+	}
+
+	kcv := types.StringNull()
+	if value, ok := keyCheckValue(kcvKey); ok {
+		kcv = types.StringValue(value)
+	}
+
+	sqlStatement := types.StringNull()
+	if !plan.SQLRole.IsNull() {
+		sqlStatement = types.StringValue(sqlDialects[plan.SQLDialect.ValueString()](plan.SQLRole.ValueString(), result))
+	}
+	return cipherKey, iv, kcv, sqlStatement
+}
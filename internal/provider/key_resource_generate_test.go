@@ -0,0 +1,89 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+func TestOldSaltsByPassword(t *testing.T) {
+	ctx := context.Background()
+	state := &KeyResourceData{
+		OldPasswords: types.ListValueMust(types.StringType, []attr.Value{types.StringValue("first"), types.StringValue("second")}),
+		OldSalts:     types.ListValueMust(types.StringType, []attr.Value{types.StringValue("c2FsdA==")}),
+	}
+
+	var diags diag.Diagnostics
+	salts := oldSaltsByPassword(ctx, state, &diags)
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	if len(salts) != 1 || string(salts["first"]) != "salt" {
+		t.Errorf("got %q", salts)
+	}
+	if salts := oldSaltsByPassword(ctx, nil, &diags); len(salts) != 0 {
+		t.Errorf("got %q without state", salts)
+	}
+}
+
+func TestCheckExpectedResults(t *testing.T) {
+	cases := map[string]struct {
+		pattern types.String
+		next    types.String
+		wantErr bool
+	}{
+		"unset":      {pattern: types.StringNull(), next: types.StringValue("other")},
+		"match":      {pattern: types.StringValue(`^\$pbkdf2`), next: types.StringNull()},
+		"next":       {pattern: types.StringValue(`^\$pbkdf2`), next: types.StringValue("other"), wantErr: true},
+		"invalid re": {pattern: types.StringValue(`(`), next: types.StringNull(), wantErr: true},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			var diags diag.Diagnostics
+			checkExpectedResults(KeyResourceData{ExpectedPattern: c.pattern, NextResult: c.next}, []string{"$pbkdf2-sha256$1$c2FsdA$a2V5"}, &diags)
+			if diags.HasError() != c.wantErr {
+				t.Errorf("got %v", diags)
+			}
+		})
+	}
+}
+
+func TestKeyOutputs(t *testing.T) {
+	dk := []byte("0123456789abcdef0123456789abcdef")
+	plan := KeyResourceData{IvLength: types.Int64Value(16), SQLRole: types.StringNull()}
+
+	cipherKey, iv, _, sqlStatement := keyOutputs(plan, dk, "result")
+	if cipherKey.ValueString() != "MDEyMzQ1Njc4OWFiY2RlZg==" || iv.ValueString() != "MDEyMzQ1Njc4OWFiY2RlZg==" || !sqlStatement.IsNull() {
+		t.Errorf("got cipher_key %v, iv %v and sql_statement %v", cipherKey, iv, sqlStatement)
+	}
+
+	cipherKey, iv, _, _ = keyOutputs(KeyResourceData{IvLength: types.Int64Null(), SQLRole: types.StringNull()}, dk, "result")
+	if !cipherKey.IsNull() || !iv.IsNull() {
+		t.Errorf("got cipher_key %v and iv %v without iv_length", cipherKey, iv)
+	}
+}
+
+func TestKeyCreatedAt(t *testing.T) {
+	const createdAt = "2024-01-02T03:04:05Z"
+	state := &KeyResourceData{
+		Salt:        types.StringValue("c2FsdA=="),
+		Key:         types.StringValue("a2V5"),
+		Attestation: types.StringValue(`{"created_at":"` + createdAt + `"}`),
+	}
+
+	if got := keyCreatedAt(state, []byte("salt"), []byte("key")); got != createdAt {
+		t.Errorf("got %s for the same salt and key", got)
+	}
+	for name, material := range map[string][2]string{"salt": {"pepper", "key"}, "key": {"salt", "other"}} {
+		if got := keyCreatedAt(state, []byte(material[0]), []byte(material[1])); got == createdAt {
+			t.Errorf("kept created_at with another %s", name)
+		}
+	}
+	if got := keyCreatedAt(nil, []byte("salt"), []byte("key")); got == createdAt {
+		t.Error("kept created_at without state")
+	}
+}
@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.ResourceWithImportState = &KeyResource{}
+)
+
+// ImportState adopts a PBKDF2 hash deployed outside Terraform from an import ID in one of the layouts
+// pbkdf2kit.ParseHash understands. The password is not part of the ID, so the first apply records it
+// and keeps the imported salt.
+func (r *KeyResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	parsed, err := pbkdf2kit.ParseHash(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Import ID",
+			"Expected a PBKDF2 hash like $pbkdf2-sha256$<iterations>$<b64 salt>$<b64 key> or pbkdf2_sha256$<iterations>$<salt>$<b64 key>: "+err.Error())
+		return
+	}
+	p, ok := pbkdf2kit.LookupPRF(parsed.HashAlgorithm)
+	if !ok || p.Alias != parsed.HashAlgorithm {
+		resp.Diagnostics.AddError("Invalid Import ID", fmt.Sprintf("The hash uses %q, which is none of %s.", parsed.HashAlgorithm, strings.Join(prfAliases(), ", ")))
+		return
+	}
+	if parsed.Iterations < 1 || len(parsed.Key) == 0 {
+		resp.Diagnostics.AddError("Invalid Import ID", "The hash needs at least one iteration and a key.")
+		return
+	}
+
+	data := importedKeyData(parsed, p, r.provider.defaultFormat())
+	result, err := pbkdf2kit.FormatTemplate(data.Format.ValueString(), pbkdf2kit.Material{
+		Iterations:    parsed.Iterations,
+		Salt:          parsed.Salt,
+		Key:           parsed.Key,
+		HashAlgorithm: p.Alias,
+	})
+	if err != nil {
+		addFormatError(&resp.Diagnostics, err, r.provider.redactErrors())
+		return
+	}
+	data.Result = types.StringValue(result)
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// importedKeyData is the state of a key imported from parsed, with every optional argument at its default.
+func importedKeyData(parsed pbkdf2kit.ParsedHash, p pbkdf2kit.PRF, format string) KeyResourceData {
+	keyLength := types.Int64Null()
+	if len(parsed.Key) != p.Size {
+		keyLength = types.Int64Value(int64(len(parsed.Key)))
+	}
+	return KeyResourceData{
+		Iterations:         types.Int64Value(int64(parsed.Iterations)),
+		Format:             types.StringValue(format),
+		OldPasswords:       types.ListNull(types.StringType),
+		Promotions:         types.Int64Value(0),
+		Prf:                types.StringValue(p.Name),
+		HashAlgorithm:      types.StringValue(p.Alias),
+		ReplaceOn:          types.ListValueMust(types.StringType, replaceOnDefault()),
+		Keepers:            types.MapNull(types.StringType),
+		PreHash:            types.BoolValue(false),
+		PepperMode:         types.StringValue("hmac"),
+		SaltLength:         types.Int64Value(int64(len(parsed.Salt))),
+		SaltCharset:        types.StringValue("bytes"),
+		SaltInputEncoding:  types.StringValue("base64"),
+		KeyLength:          keyLength,
+		DeletionProtection: types.BoolValue(false),
+		ForceDestroy:       types.BoolValue(false),
+		SQLDialect:         types.StringValue("postgresql"),
+		Tags:               types.MapNull(types.StringType),
+		Recipients:         types.ListNull(types.StringType),
+		Salt:               types.StringValue(base64.StdEncoding.EncodeToString(parsed.Salt)),
+		Key:                types.StringValue(base64.StdEncoding.EncodeToString(parsed.Key)),
+		EncryptedShares:    types.ListNull(types.StringType),
+		OldResults:         types.ListValueMust(types.StringType, []attr.Value{}),
+		OldSalts:           types.ListValueMust(types.StringType, []attr.Value{}),
+	}
+}
+
+// imported reports whether state comes from ImportState and has not been applied since, so it holds a key
+// but not the password it derives from.
+func imported(state *KeyResourceData) bool {
+	return state != nil && state.Password.IsNull() && state.PasswordWoVersion.IsNull() && !state.Salt.IsNull() && !state.Key.IsNull()
+}
+
+// checkImportedKey warns when the configured password and parameters don't derive to the imported key,
+// as the first apply then changes it.
+func checkImportedKey(ctx context.Context, state *KeyResourceData, provider *pbkdf2ProviderData, resp *resource.ModifyPlanResponse) {
+	if resp.Diagnostics.HasError() || !imported(state) {
+		return
+	}
+
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() || !plan.SaltSeed.IsNull() || !plan.SaltInput.IsNull() || !plan.PepperWoVersion.IsNull() || !plan.PasswordWoVersion.IsNull() {
+		return
+	}
+	for _, value := range []attr.Value{plan.Password, plan.Iterations, plan.Prf, plan.KeyLength, plan.IvLength, plan.CipherKeyLength} {
+		if value.IsUnknown() {
+			return
+		}
+	}
+
+	// Only the key is compared, as a format the configuration changes doesn't touch it.
+	dk, _, _ := derive(plan, provider, plan.Password.ValueString(), stateBytes(state.Salt))
+	if base64.StdEncoding.EncodeToString(dk) == state.Key.ValueString() {
+		return
+	}
+	resp.Diagnostics.AddAttributeWarning(path.Root("password"), "Imported Key Not Matched",
+		"The password doesn't derive to the imported key with the configured prf, iterations, key_length and pepper, "+
+			"so the first apply changes the key. Consumers of the imported hash have to be updated.")
+}
+
+// checkImportedSalt warns when the configured salt_length or salt_charset doesn't describe the imported salt.
+// The first apply keeps the imported salt regardless, so they only shape salts generated later.
+func checkImportedSalt(ctx context.Context, config KeyResourceData, state *KeyResourceData, resp *resource.ModifyPlanResponse) {
+	if resp.Diagnostics.HasError() || !imported(state) || !config.SaltSeed.IsNull() || !config.SaltInput.IsNull() {
+		return
+	}
+
+	var plan KeyResourceData
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	salt := stateBytes(state.Salt)
+	if !config.SaltLength.IsNull() && !plan.SaltLength.IsUnknown() && plan.SaltLength.ValueInt64() != int64(len(salt)) {
+		resp.Diagnostics.AddAttributeWarning(path.Root("salt_length"), "Imported Salt Kept",
+			fmt.Sprintf("The imported salt is %d bytes long, not salt_length. The first apply keeps it, and salt_length only applies to salts generated later.", len(salt)))
+	}
+	// A salt made of nothing but the charset's characters trims to nothing.
+	if chars, ok := saltCharsets[plan.SaltCharset.ValueString()]; ok && strings.Trim(string(salt), chars) != "" {
+		resp.Diagnostics.AddAttributeWarning(path.Root("salt_charset"), "Imported Salt Kept",
+			"The imported salt has characters outside salt_charset. The first apply keeps it, and salt_charset only applies to salts generated later.")
+	}
+}
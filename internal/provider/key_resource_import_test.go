@@ -0,0 +1,111 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+)
+
+const testAccKeyResourceImportConfig = `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+}
+`
+
+func TestAccKeyResource_import(t *testing.T) {
+	const result = "c2FsdHNhbHRzYWx0c2FsdA==:8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             testAccKeyResourceImportConfig,
+				ResourceName:       "pbkdf2_key.test",
+				ImportState:        true,
+				ImportStateId:      "$pbkdf2-sha256$1000$c2FsdHNhbHRzYWx0c2FsdA$8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg",
+				ImportStatePersist: true,
+				ImportStateCheck: func(states []*terraform.InstanceState) error {
+					if len(states) != 1 {
+						return fmt.Errorf("expected 1 state, got %d", len(states))
+					}
+					attributes := states[0].Attributes
+					for name, want := range map[string]string{
+						"prf":        "hmac-sha256",
+						"iterations": "1000",
+						"salt":       "c2FsdHNhbHRzYWx0c2FsdA==",
+						"result":     result,
+					} {
+						if attributes[name] != want {
+							return fmt.Errorf("%s: got %q, want %q", name, attributes[name], want)
+						}
+					}
+					return nil
+				},
+			},
+			{
+				// The password derives to the imported key, so applying it keeps the key.
+				Config: testAccKeyResourceImportConfig,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "salt", "c2FsdHNhbHRzYWx0c2FsdA=="),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "result", result),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_importSaltKept(t *testing.T) {
+	const config = `
+resource "pbkdf2_key" "test" {
+  password     = "one"
+  iterations   = 1000
+  salt_length  = 12
+  salt_charset = "hex"
+}
+`
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:             config,
+				ResourceName:       "pbkdf2_key.test",
+				ImportState:        true,
+				ImportStateId:      "$pbkdf2-sha256$1000$c2FsdHNhbHRzYWx0c2FsdA$8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg",
+				ImportStatePersist: true,
+			},
+			{
+				// The imported salt fits neither salt_length nor salt_charset, but is kept with a warning.
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "salt", "c2FsdHNhbHRzYWx0c2FsdA=="),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "salt_charset", "hex"),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_importInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:        testAccKeyResourceImportConfig,
+				ResourceName:  "pbkdf2_key.test",
+				ImportState:   true,
+				ImportStateId: "$pbkdf2-md5$1000$c2FsdA$a2V5",
+				ExpectError:   regexp.MustCompile(`Invalid Import ID`),
+			},
+		},
+	})
+}
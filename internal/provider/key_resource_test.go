@@ -1,10 +1,26 @@
 package provider
 
 import (
+	"bytes"
+	"encoding/base64"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/knownvalue"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/hashicorp/terraform-plugin-testing/tfjsonpath"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
 )
 
 func TestAccKeyResource(t *testing.T) {
@@ -17,6 +33,9 @@ func TestAccKeyResource(t *testing.T) {
 				Check: resource.ComposeAggregateTestCheckFunc(
 					resource.TestCheckResourceAttr("pbkdf2_key.test", "password", "one"),
 					resource.TestCheckResourceAttr("pbkdf2_key.test", "iterations", "100000"),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "prf", "hmac-sha256"),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "hash_algorithm", "sha256"),
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "attestation", regexp.MustCompile(`"prf":"hmac-sha256","iterations":100000,"salt_length":16,"key_length":32,"provider_version":"test"`)),
 				),
 			},
 			{
@@ -29,11 +48,1809 @@ func TestAccKeyResource(t *testing.T) {
 	})
 }
 
+func TestAccKeyResource_hashAlgorithmAlias(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password       = "one"
+  hash_algorithm = "sha512"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "prf", "hmac-sha512"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password       = "one"
+  hash_algorithm = "sha1"
+  iterations     = 1000
+  salt_seed      = "0123456789abcdef"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "prf", "hmac-sha1"),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "result", "/MRMqguzsRltpX9hnzXiDQ==:uNBhPBx20AiD5ETpX/gln9kIKpc="),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password       = "one"
+  hash_algorithm = "sha-512"
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)value must be one of:.*"sha512"`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_oldPasswords(t *testing.T) {
+	var oldResults [2]string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "three"
+  old_passwords = ["one", "two"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "old_results.#", "2"),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "old_salts.#", "2"),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "old_results.0", func(value string) error {
+						oldResults[0] = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "old_results.1", func(value string) error {
+						oldResults[1] = value
+						return nil
+					}),
+				),
+			},
+			{
+				// Rotating the password keeps the history entries that are still listed.
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "four"
+  old_passwords = ["three", "one", "two"]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "old_results.#", "3"),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "old_results.1", func(value string) error {
+						if value != oldResults[0] {
+							return fmt.Errorf("old_results for an unchanged entry changed")
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "old_results.2", func(value string) error {
+						if value != oldResults[1] {
+							return fmt.Errorf("old_results for an unchanged entry changed")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_promote(t *testing.T) {
+	var nextKey string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  next_password = "two"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "next_key", func(value string) error {
+						nextKey = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "two"
+  promotions = 1
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "key", func(value string) error {
+						if value != nextKey {
+							return fmt.Errorf("promoted key does not match the previous next_key")
+						}
+						return nil
+					}),
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "next_key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_targetDuration(t *testing.T) {
+	var iterations string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourceTargetDurationConfig("one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "iterations", func(value string) error {
+						iterations = value
+						return nil
+					}),
+				),
+			},
+			{
+				// Changing the password must keep the calibrated iterations.
+				Config: testAccKeyResourceTargetDurationConfig("two"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "iterations", func(value string) error {
+						if value != iterations {
+							return fmt.Errorf("iterations changed from %s to %s", iterations, value)
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password           = "two"
+  target_duration_ms = 0
+}
+`,
+				ExpectError: regexp.MustCompile(`Attribute target_duration_ms value must be at least 1`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_replaceOn(t *testing.T) {
+	var salt, key string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourceReplaceOnConfig("one", 1000),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						salt = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "key", func(value string) error {
+						key = value
+						return nil
+					}),
+				),
+			},
+			{
+				// iterations is not in replace_on, so the key is re-derived with the same salt.
+				Config: testAccKeyResourceReplaceOnConfig("one", 2000),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						if value != salt {
+							return fmt.Errorf("salt changed although iterations is not in replace_on")
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "key", func(value string) error {
+						if value == key {
+							return fmt.Errorf("key was not re-derived")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccKeyResourceReplaceOnConfig("two", 2000),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						if value == salt {
+							return fmt.Errorf("salt kept although password is in replace_on")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_deletionProtection(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourceDeletionProtectionConfig(false),
+			},
+			{
+				Config:      testAccKeyResourceDeletionProtectionConfig(false),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile(`Deletion Protection`),
+			},
+			{
+				Config: testAccKeyResourceDeletionProtectionConfig(true),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_weakPasswordCheckDisabled(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// The check is off unless min_password_score is set.
+				Config: `
+resource "pbkdf2_key" "test" {
+  password = "sunshine"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "password", "sunshine"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_placeholderPassword(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  reject_placeholder_passwords = true
+}
+
+resource "pbkdf2_key" "test" {
+  password = "ChangeMe"
+}
+`,
+				ExpectError: regexp.MustCompile(`Placeholder Password`),
+			},
+			{
+				Config: `
+provider "pbkdf2" {
+  placeholder_password_pattern = "^TODO-"
+  reject_placeholder_passwords = true
+}
+
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  next_password = "TODO-rotate"
+}
+`,
+				ExpectError: regexp.MustCompile(`Placeholder Password`),
+			},
+			{
+				// Without reject_placeholder_passwords placeholders are only warned about.
+				Config: `
+resource "pbkdf2_key" "test" {
+  password = "ChangeMe"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pbkdf2_key.test", "key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_pepper(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourcePepperConfig("concat", "one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "0"),
+				),
+			},
+			{
+				Config: testAccKeyResourcePepperConfig("hmac", "one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "pepper_mode", "hmac"),
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "0"),
+				),
+			},
+			{
+				// The provider pepper is applied by pbkdf2_verify, so a hand-peppered password doesn't match.
+				Config: testAccKeyResourcePepperConfig("hmac", "onepepper"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_verifyOnRefresh(t *testing.T) {
+	var salt string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourceVerifyOnRefreshConfig("one"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						salt = value
+						return nil
+					}),
+				),
+			},
+			{
+				// The rotated pepper no longer derives the stored key, so the key is created anew.
+				Config: testAccKeyResourceVerifyOnRefreshConfig("two"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						if value == salt {
+							return fmt.Errorf("salt was kept although the key was replaced")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccKeyResourceVerifyOnRefreshConfig(pepper string) string {
+	return fmt.Sprintf(`
+provider "pbkdf2" {
+  pepper            = %[1]q
+  verify_on_refresh = true
+}
+
+resource "pbkdf2_key" "test" {
+  password = "one"
+}
+`, pepper)
+}
+
+func TestAccKeyResource_resourcePepper(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  pepper = "pepper"
+}
+
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  pepper      = "tenant"
+  pepper_mode = "concat"
+}
+
+data "pbkdf2_verify" "test" {
+  password    = "one"
+  hashes      = [pbkdf2_key.test.result]
+  pepper      = "tenant"
+  pepper_mode = "concat"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_pepperWriteOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password          = "one"
+  pepper_wo         = "tenant"
+  pepper_wo_version = "1"
+  pepper_mode       = "concat"
+}
+
+data "pbkdf2_verify" "test" {
+  password = "onetenant"
+  hashes   = [pbkdf2_key.test.result]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "pepper_wo"),
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_passwordWriteOnly(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_11_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourcePasswordWoConfig("one", "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "password"),
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "password_wo"),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "password_wo_version", "1"),
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "0"),
+				),
+			},
+			{
+				// Without a new version the changed password is not noticed.
+				Config:   testAccKeyResourcePasswordWoConfig("two", "1"),
+				PlanOnly: true,
+			},
+			{
+				Config: testAccKeyResourcePasswordWoConfig("two", "2"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "password_wo_version", "2"),
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccKeyResourcePasswordWoConfig(password, version string) string {
+	return fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password_wo         = %[1]q
+  password_wo_version = %[2]q
+}
+
+data "pbkdf2_verify" "test" {
+  password = %[1]q
+  hashes   = [pbkdf2_key.test.result]
+}
+`, password, version)
+}
+
+func TestAccKeyResource_preHash(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password = "one"
+  pre_hash = true
+}
+
+data "pbkdf2_verify" "test" {
+  password = "one"
+  hashes   = [pbkdf2_key.test.result]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "pre_hash", "true"),
+					// The key is derived from the digest, not from the password itself.
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "-1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_cipherKeyAndIV(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password  = "one"
+  iv_length = 16
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pbkdf2_key.test", "cipher_key"),
+					resource.TestCheckResourceAttrSet("pbkdf2_key.test", "iv"),
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "attestation", regexp.MustCompile(`"key_length":48`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_integrityTag(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  integrity_key = "state-secret"
+}
+
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  next_password = "two"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "integrity_tag", regexp.MustCompile(`^[0-9a-f]{64}$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_formatPreset(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  format_preset = "tomcat"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "result", regexp.MustCompile(`^[0-9a-f]{32}\$1000\$[0-9a-f]{64}$`)),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  format_preset = "mosquitto"
+}
+`,
+				ExpectError: regexp.MustCompile(`requires prf = "hmac-sha512"`),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  prf           = "hmac-streebog512"
+  format_preset = "mediawiki"
+}
+`,
+				ExpectError: regexp.MustCompile(`requires prf to be one of`),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  salt_charset  = "alphanumeric"
+  key_length    = 16
+  format_preset = "django"
+}
+`,
+				ExpectError: regexp.MustCompile(`requires a key of the 32 byte output size of hmac-sha256`),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  prf           = "hmac-sha512"
+  format_preset = "mosquitto"
+}
+`,
+				ExpectError: regexp.MustCompile(`requires a 12 byte salt, got 16 bytes`),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  prf           = "hmac-sha512"
+  salt_length   = 12
+  format_preset = "mosquitto"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "result", regexp.MustCompile(`^\$7\$1000\$[A-Za-z0-9+/]{16}\$[A-Za-z0-9+/]{86}==$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_formatContext(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+  prf        = "hmac-sha512"
+  format     = "pbkdf2_{{ .HashAlgorithm }}:{{ .Iterations }}:{{ .SaltLength }}:{{ .KeyLength }}:{{ .Password }}"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "result", "pbkdf2_sha512:1000:16:64:one"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_formatPresetTextSalt(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  format_preset = "django"
+}
+`,
+				ExpectError: regexp.MustCompile(`stores the salt as text`),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  salt_charset  = "alphanumeric"
+  format_preset = "django"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "result", regexp.MustCompile(`^pbkdf2_sha256\$1000\$[A-Za-z0-9]{16}\$[A-Za-z0-9+/]{43}=$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_sqlStatement(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 4096
+  format_preset = "postgresql_scram"
+  sql_role      = "app"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "sql_statement",
+						regexp.MustCompile(`^ALTER ROLE "app" PASSWORD 'SCRAM-SHA-256\$4096:[A-Za-z0-9+/]{22}==\$[A-Za-z0-9+/]{43}=:[A-Za-z0-9+/]{43}='$`)),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 4096
+  format_preset = "postgresql_scram"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "sql_statement"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_formatError(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password = "one"
+  format   = "{{ .Bar }}"
+}
+`,
+				ExpectError: regexp.MustCompile(`Line 1, column 3, at <\.Bar>: can't evaluate field Bar in the format data`),
+			},
+			{
+				// Broken templates fail the plan, before a salt is generated.
+				Config: `
+resource "pbkdf2_key" "test" {
+  password = "one"
+  format   = "{{ b64enc .Salt | nosuchfunc }}"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Line 1: function "nosuchfunc" not defined`),
+			},
+			{
+				Config: `
+provider "pbkdf2" {
+  redact_errors = true
+}
+
+resource "pbkdf2_key" "test" {
+  password = "one"
+  format   = "{{ index .Key 100 }}"
+}
+`,
+				// Only the apply renders the actual 32 byte key, so this fails there.
+				ExpectError: regexp.MustCompile(`Line 1, column 3: the format template failed \(details redacted\)`),
+			},
+		},
+	})
+}
+
+func testAccKeyResourcePepperConfig(mode, candidate string) string {
+	return fmt.Sprintf(`
+provider "pbkdf2" {
+  pepper = "pepper"
+}
+
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  pepper_mode = %[1]q
+}
+
+data "pbkdf2_verify" "test" {
+  password    = %[2]q
+  hashes      = [pbkdf2_key.test.result]
+  pepper_mode = %[1]q
+}
+`, mode, candidate)
+}
+
+func testAccKeyResourceDeletionProtectionConfig(forceDestroy bool) string {
+	return fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password            = "one"
+  deletion_protection = true
+  force_destroy       = %[1]t
+}
+`, forceDestroy)
+}
+
+func TestAccKeyResource_formatKeepsSalt(t *testing.T) {
+	var salt, key string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						salt = value
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "key", func(value string) error {
+						key = value
+						return nil
+					}),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+  format     = "{{ b64enc .Key }}"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						if value != salt {
+							return fmt.Errorf("salt changed although only format did")
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "result", func(value string) error {
+						if value != key {
+							return fmt.Errorf("result was not re-rendered from the stored key")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_keepers(t *testing.T) {
+	var salt string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourceKeepersConfig("one", "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						salt = value
+						return nil
+					}),
+				),
+			},
+			{
+				// replace_on is empty, so a new password is derived with the stored salt.
+				Config: testAccKeyResourceKeepersConfig("two", "1"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						if value != salt {
+							return fmt.Errorf("salt changed although the keepers didn't")
+						}
+						return nil
+					}),
+				),
+			},
+			{
+				Config: testAccKeyResourceKeepersConfig("two", "2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("pbkdf2_key.test", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "salt", func(value string) error {
+						if value == salt {
+							return fmt.Errorf("salt kept although a keeper changed")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func testAccKeyResourceKeepersConfig(password, rotation string) string {
+	return fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password   = %[1]q
+  iterations = 1000
+  replace_on = []
+  keepers = {
+    rotation = %[2]q
+  }
+}
+`, password, rotation)
+}
+
+func testAccKeyResourceReplaceOnConfig(password string, iterations int) string {
+	return fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password   = %[1]q
+  iterations = %[2]d
+  replace_on = ["password"]
+}
+`, password, iterations)
+}
+
+func testAccKeyResourceTargetDurationConfig(password string) string {
+	return fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password           = %[1]q
+  target_duration_ms = 10
+}
+`, password)
+}
+
 func testAccKeyResourceConfig(password string) string {
 	return fmt.Sprintf(`
 resource "pbkdf2_key" "test" {
-  password = %[1]q
-  format   = "v01{{bin 3 .Iterations}}{{printf \"%%s\" .Key}}"
+  password = %[1]q
+  format   = "v01{{bin 3 .Iterations}}{{printf \"%%s\" .Key}}"
+}
+`, password)
+}
+
+func TestAccKeyResource_providerDefaults(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  default_prf         = "hmac-sha512"
+  default_salt_length = 24
+  default_format      = "{{ b64enc .Key }}"
+}
+
+resource "pbkdf2_key" "defaults" {
+  password   = "one"
+  iterations = 1000
+}
+
+resource "pbkdf2_key" "overridden" {
+  password    = "one"
+  iterations  = 1000
+  prf         = "hmac-sha256"
+  salt_length = 16
+  format      = "{{ b64enc .Salt }}"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.defaults", "prf", "hmac-sha512"),
+					resource.TestCheckResourceAttr("pbkdf2_key.defaults", "hash_algorithm", "sha512"),
+					resource.TestCheckResourceAttr("pbkdf2_key.defaults", "salt_length", "24"),
+					resource.TestCheckResourceAttr("pbkdf2_key.defaults", "format", "{{ b64enc .Key }}"),
+					resource.TestMatchResourceAttr("pbkdf2_key.defaults", "result", regexp.MustCompile(`^[A-Za-z0-9+/]{86}==$`)),
+					resource.TestCheckResourceAttr("pbkdf2_key.overridden", "prf", "hmac-sha256"),
+					resource.TestCheckResourceAttr("pbkdf2_key.overridden", "salt_length", "16"),
+					resource.TestMatchResourceAttr("pbkdf2_key.overridden", "result", regexp.MustCompile(`^[A-Za-z0-9+/]{22}==$`)),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_defaultIterations(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  default_iterations = 2000
+}
+
+resource "pbkdf2_key" "test" {
+  password = "one"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "iterations", "2000"),
+				),
+			},
+			{
+				PreConfig: func() { t.Setenv("PBKDF2_DEFAULT_ITERATIONS", "3000") },
+				Config: `
+resource "pbkdf2_key" "test" {
+  password = "one"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "iterations", "3000"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_parallelism(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				PreConfig: func() { t.Setenv("PBKDF2_PARALLELISM", "0") },
+				Config: `
+resource "pbkdf2_key" "test" {
+  count    = 3
+  password = "one"
+}
+`,
+				ExpectError: regexp.MustCompile(`PBKDF2_PARALLELISM must be a positive integer`),
+			},
+			{
+				PreConfig: func() { t.Setenv("PBKDF2_PARALLELISM", "1") },
+				Config: `
+resource "pbkdf2_key" "test" {
+  count    = 3
+  password = "one"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrSet("pbkdf2_key.test.0", "key"),
+					resource.TestCheckResourceAttrSet("pbkdf2_key.test.1", "key"),
+					resource.TestCheckResourceAttrSet("pbkdf2_key.test.2", "key"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_fipsMode(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "pbkdf2" {
+  fips_mode = true
+}
+
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  iterations  = 500
+  salt_length = 8
+}
+`,
+				ExpectError: regexp.MustCompile(`(?s)at least 16 bytes, got 8.*at least 1000 iterations, got 500`),
+			},
+			{
+				PreConfig: func() { t.Setenv("PBKDF2_FIPS_MODE", "true") },
+				Config: `
+resource "pbkdf2_key" "test" {
+  password          = "one"
+  cipher_key_length = 8
+  iv_length         = 8
+}
+`,
+				ExpectError: regexp.MustCompile(`at least 112 bits, got 8 bytes`),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password = "one"
+}
+`,
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_derivationContext(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourceDerivationContextConfig("staging"),
+			},
+			{
+				// The stored key no longer derives from the inputs under another context.
+				Config:      testAccKeyResourceDerivationContextConfig("production"),
+				ExpectError: regexp.MustCompile(`derivation_context changed`),
+			},
+			{
+				Config: testAccKeyResourceDerivationContextConfig("staging"),
+			},
+			{
+				// Django couldn't verify a hash whose salt carries the context.
+				Config: `
+provider "pbkdf2" {
+  derivation_context = "staging"
+}
+
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  iterations    = 1000
+  format_preset = "django"
+}
+`,
+				ExpectError: regexp.MustCompile(`Derivation Context Conflict`),
+			},
+		},
+	})
+}
+
+func testAccKeyResourceDerivationContextConfig(derivationContext string) string {
+	return fmt.Sprintf(`
+provider "pbkdf2" {
+  derivation_context = %[1]q
+}
+
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+}
+`, derivationContext)
+}
+
+// attestationCreatedAt matches the created_at field of an attestation.
+var attestationCreatedAt = regexp.MustCompile(`"created_at":"[^"]*"`)
+
+func TestAccKeyResource_descriptionAndTags(t *testing.T) {
+	var createdAt string
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  iterations  = 1000
+  description = "billing database"
+  tags = {
+    owner = "payments"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "tags.owner", "payments"),
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "attestation", regexp.MustCompile(`"description":"billing database","tags":\{"owner":"payments"\}`)),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "attestation", func(value string) error {
+						createdAt = attestationCreatedAt.FindString(value)
+						return nil
+					}),
+				),
+			},
+			{
+				// The key is kept, and so is its creation time.
+				PreConfig: func() { time.Sleep(time.Second) },
+				Config: `
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  iterations  = 1000
+  description = "billing and invoicing database"
+  tags = {
+    owner = "finance"
+  }
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "attestation", regexp.MustCompile(`"description":"billing and invoicing database","tags":\{"owner":"finance"\}`)),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "attestation", func(value string) error {
+						if got := attestationCreatedAt.FindString(value); got == "" || got != createdAt {
+							return fmt.Errorf("created_at changed from %s to %s", createdAt, got)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_securityLevel(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password       = "one"
+  prf            = "hmac-sha512"
+  security_level = "interactive"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "iterations", "210000"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password       = "one"
+  iterations     = 1000
+  security_level = "interactive"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Attribute Combination`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_recipients(t *testing.T) {
+	identity, err := age.GenerateX25519Identity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decryptsTo := func(length int) resource.CheckResourceAttrWithFunc {
+		return func(value string) error {
+			r, err := age.Decrypt(armor.NewReader(strings.NewReader(value)), identity)
+			if err != nil {
+				return err
+			}
+			plaintext, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			if len(plaintext) != length {
+				return fmt.Errorf("decrypted %d bytes, want %d", len(plaintext), length)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+  recipients = [%q]
+}
+`, identity.Recipient().String()),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "key"),
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "result"),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "encrypted_key", decryptsTo(32)),
+					// base64 of the 16 byte salt, a colon and the 32 byte key.
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "encrypted_result", decryptsTo(69)),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+  recipients = ["age1invalid"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Recipient`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_saltSeed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "a" {
+  password   = "one"
+  iterations = 1000
+  salt_seed  = "0123456789abcdef"
+}
+
+resource "pbkdf2_key" "b" {
+  password   = "one"
+  iterations = 1000
+  salt_seed  = "0123456789abcdef"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttrPair("pbkdf2_key.a", "salt", "pbkdf2_key.b", "salt"),
+					resource.TestCheckResourceAttrPair("pbkdf2_key.a", "result", "pbkdf2_key.b", "result"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_saltSeedKnownAtPlan(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+  salt_seed  = "0123456789abcdef"
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectKnownValue("pbkdf2_key.test", tfjsonpath.New("salt"), knownvalue.NotNull()),
+						plancheck.ExpectKnownValue("pbkdf2_key.test", tfjsonpath.New("key"), knownvalue.NotNull()),
+						plancheck.ExpectKnownValue("pbkdf2_key.test", tfjsonpath.New("result"), knownvalue.NotNull()),
+						plancheck.ExpectKnownValue("pbkdf2_key.test", tfjsonpath.New("kcv"), knownvalue.NotNull()),
+					},
+				},
+			},
+			{
+				// Without a seed the salt is random, so the result is only known after apply.
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "two"
+  iterations = 1000
+}
+`,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectUnknownValue("pbkdf2_key.test", tfjsonpath.New("result")),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_formatFile(t *testing.T) {
+	formatFile := filepath.Join(t.TempDir(), "key.tmpl")
+	if err := os.WriteFile(formatFile, []byte("{{ .Iterations }}${{ b64enc .Key }}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	brokenFormatFile := filepath.Join(t.TempDir(), "broken.tmpl")
+	if err := os.WriteFile(brokenFormatFile, []byte("{{ b64enc .Key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  iterations  = 1000
+  format_file = %q
+}
+`, formatFile),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "format", "{{ .Iterations }}${{ b64enc .Key }}"),
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "result", regexp.MustCompile(`^1000\$[A-Za-z0-9+/]{43}=$`)),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  iterations  = 1000
+  format_file = "does-not-exist.tmpl"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Format File`),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password    = "one"
+  iterations  = 1000
+  format_file = %q
+}
+`, brokenFormatFile),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Line 1: unclosed action`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_expectedPattern(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password         = "one"
+  iterations       = 1000
+  format_preset    = "tomcat"
+  expected_pattern = "^[0-9a-f]{32}\\$1000\\$[0-9a-f]{64}$"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "result", regexp.MustCompile(`^[0-9a-f]{32}\$1000\$`)),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password         = "one"
+  iterations       = 1000
+  format           = "{{ .Iterations }}"
+  expected_pattern = "^\\$7\\$"
+}
+`,
+				ExpectError: regexp.MustCompile(`Unexpected Result`),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password         = "one"
+  iterations       = 1000
+  expected_pattern = "("
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Expected Pattern`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_kcv(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestMatchResourceAttr("pbkdf2_key.test", "kcv", regexp.MustCompile(`^[0-9A-F]{6}$`)),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password          = "one"
+  iterations        = 1000
+  cipher_key_length = 20
+  iv_length         = 16
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "kcv"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_shareThreshold(t *testing.T) {
+	identities := make([]*age.X25519Identity, 3)
+	recipients := make([]string, 3)
+	for i := range identities {
+		identity, err := age.GenerateX25519Identity()
+		if err != nil {
+			t.Fatal(err)
+		}
+		identities[i] = identity
+		recipients[i] = fmt.Sprintf("%q", identity.Recipient().String())
+	}
+	var shares [][]byte
+	decryptShare := func(i int) resource.CheckResourceAttrWithFunc {
+		return func(value string) error {
+			r, err := age.Decrypt(armor.NewReader(strings.NewReader(value)), identities[i])
+			if err != nil {
+				return err
+			}
+			share, err := io.ReadAll(r)
+			if err != nil {
+				return err
+			}
+			shares = append(shares, share)
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password        = "one"
+  iterations      = 1000
+  recipients      = [%s]
+  share_threshold = 2
+}
+`, strings.Join(recipients, ", ")),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "encrypted_key"),
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "encrypted_shares.#", "3"),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "encrypted_shares.0", decryptShare(0)),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.test", "encrypted_shares.2", decryptShare(2)),
+					func(*terraform.State) error {
+						key, err := shamirCombine(shares)
+						if err != nil {
+							return err
+						}
+						if len(key) != 32 {
+							return fmt.Errorf("recovered a %d byte key", len(key))
+						}
+						return nil
+					},
+				),
+			},
+			{
+				Config: fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password        = "one"
+  iterations      = 1000
+  recipients      = [%s]
+  share_threshold = 4
+}
+`, strings.Join(recipients, ", ")),
+				ExpectError: regexp.MustCompile(`Invalid Share Threshold`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_existingHash(t *testing.T) {
+	const existingHash = "c2FsdHNhbHRzYWx0c2FsdA==:8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+resource "pbkdf2_key" "adopted" {
+  password      = "one"
+  iterations    = 1000
+  existing_hash = %[1]q
+}
+
+resource "pbkdf2_key" "rotated" {
+  password      = "two"
+  iterations    = 1000
+  existing_hash = %[1]q
+}
+`, existingHash),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.adopted", "result", existingHash),
+					resource.TestCheckResourceAttr("pbkdf2_key.adopted", "salt", "c2FsdHNhbHRzYWx0c2FsdA=="),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.rotated", "result", func(value string) error {
+						if value == existingHash {
+							return fmt.Errorf("adopted a hash the password doesn't derive to")
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_keyedBlake2b(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  prf        = "keyed-blake2b-256"
+  iterations = 1000
+  salt_seed  = "0123456789abcdef"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "result", "/MRMqguzsRltpX9hnzXiDQ==:NRkdsIzviE4TAY2ruEwZwl/v49rD81gQ/9AScI3cvt8="),
+					resource.TestCheckNoResourceAttr("pbkdf2_key.test", "hash_algorithm"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password      = "one"
+  prf           = "keyed-blake2b-256"
+  iterations    = 1000
+  format_preset = "tomcat"
+}
+`,
+				ExpectError: regexp.MustCompile(`only supports HMAC PRFs`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_streebog(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  prf        = "hmac-streebog512"
+  iterations = 1000
+  salt_seed  = "0123456789abcdef"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "result",
+						"/MRMqguzsRltpX9hnzXiDQ==:iqVcHEOc0lkapOZTix+bFamaKQxQILqFTgAU6wlNxITNajc4eby5yIJkEiDlpnyROIgpFLIsjqZSAiNpFVbvqA=="),
+				),
+			},
+			{
+				Config: `
+provider "pbkdf2" {
+  fips_mode = true
+}
+
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  prf        = "hmac-streebog512"
+  iterations = 1000
+  salt_seed  = "0123456789abcdef"
+}
+`,
+				ExpectError: regexp.MustCompile(`approved hash, got "hmac-streebog512"`),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_saltCharset(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "seeded" {
+  password     = "one"
+  iterations   = 1000
+  salt_seed    = "0123456789abcdef"
+  salt_charset = "hex"
+}
+
+resource "pbkdf2_key" "random" {
+  password     = "one"
+  iterations   = 1000
+  salt_length  = 24
+  salt_charset = "alphanumeric"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.seeded", "salt", "YzRjYWIzMTlkNWYxZjUyZA=="),
+					resource.TestCheckResourceAttr("pbkdf2_key.seeded", "result", "YzRjYWIzMTlkNWYxZjUyZA==:sAST6I6ela/jisjkqJwuZu/VQn4v3iXAbb7EwXcbmCs="),
+					resource.TestCheckResourceAttrWith("pbkdf2_key.random", "salt", func(value string) error {
+						salt, err := base64.StdEncoding.DecodeString(value)
+						if err != nil {
+							return err
+						}
+						if !regexp.MustCompile(`^[A-Za-z0-9]{24}$`).Match(salt) {
+							return fmt.Errorf("salt %q is not 24 alphanumeric characters", salt)
+						}
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccKeyResource_saltInput(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_key" "base64" {
+  password   = "one"
+  iterations = 1000
+  salt_input = "c2FsdHNhbHRzYWx0c2FsdA=="
+}
+
+resource "pbkdf2_key" "hex" {
+  password            = "one"
+  iterations          = 1000
+  salt_input          = "73616c7473616c7473616c7473616c74"
+  salt_input_encoding = "hex"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.base64", "salt", "c2FsdHNhbHRzYWx0c2FsdA=="),
+					resource.TestCheckResourceAttr("pbkdf2_key.base64", "result", "c2FsdHNhbHRzYWx0c2FsdA==:8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="),
+					resource.TestCheckResourceAttrPair("pbkdf2_key.hex", "result", "pbkdf2_key.base64", "result"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_key" "base64" {
+  password   = "one"
+  iterations = 1000
+  salt_input = "not base64!"
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Salt Input`),
+			},
+		},
+	})
+}
+
+func TestSaltFrom(t *testing.T) {
+	// 255 and 248 are past the largest multiple of 62 and skipped, so the rest map uniformly.
+	salt, err := saltFrom(bytes.NewReader([]byte{255, 0, 61, 248, 62, 247}), 4, types.StringValue("alphanumeric"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(salt) != "A9A9" {
+		t.Errorf("got %q, want %q", salt, "A9A9")
+	}
+
+	salt, err = saltFrom(bytes.NewReader([]byte{255, 0}), 2, types.StringValue("bytes"))
+	if err != nil || !bytes.Equal(salt, []byte{255, 0}) {
+		t.Errorf("got %v, %v", salt, err)
+	}
+	if _, err := saltFrom(bytes.NewReader([]byte{255}), 1, types.StringValue("hex")); err != nil {
+		t.Error(err)
+	}
+	if _, err := saltFrom(bytes.NewReader([]byte{250}), 1, types.StringValue("alphanumeric")); err == nil {
+		t.Error("expected an error when the source runs out")
+	}
+}
+
+func TestAccKeyResource_keyLength(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccKeyResourceKeyLengthConfig(24),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "result", "/MRMqguzsRltpX9hnzXiDQ==:vhVd0cEelPxpO+m/O38bhsrSPELU2Ddn"),
+				),
+			},
+			{
+				// Longer than a SHA-256 block, so PBKDF2 derives two and truncates nothing.
+				Config: testAccKeyResourceKeyLengthConfig(64),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_key.test", "result",
+						"/MRMqguzsRltpX9hnzXiDQ==:vhVd0cEelPxpO+m/O38bhsrSPELU2DdnQGTcKCwLJ/dvmwYLLX4P1EyiRMAyD1DvD164iLR44q8U0UTK1+lsMA=="),
+				),
+			},
+			{
+				Config:      testAccKeyResourceKeyLengthConfig(2048),
+				ExpectError: regexp.MustCompile(`key_length value must be between 1 and 1024`),
+			},
+		},
+	})
+}
+
+func testAccKeyResourceKeyLengthConfig(keyLength int) string {
+	return fmt.Sprintf(`
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+  salt_seed  = "0123456789abcdef"
+  key_length = %d
 }
-`, password)
+`, keyLength)
 }
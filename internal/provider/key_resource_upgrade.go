@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+)
+
+// keyStateUpgrades migrate raw pbkdf2_key state one schema version at a time,
+// so state of any prior version can be brought up to date by running the tail
+// of the list.
+var keyStateUpgrades = []func(state map[string]any, provider *pbkdf2ProviderData){
+	// 0 -> 1: hash_algorithm became a deprecated alias of prf.
+	func(state map[string]any, _ *pbkdf2ProviderData) {
+		name, _ := state["hash_algorithm"].(string)
+		p, ok := pbkdf2kit.LookupPRF(name)
+		if !ok {
+			p, _ = pbkdf2kit.LookupPRF(pbkdf2kit.DefaultPRF)
+		}
+		state["prf"] = p.Name
+		state["hash_algorithm"] = p.Alias
+	},
+	// 1 -> 2: salts and keys were stored as raw bytes and became base64 encoded.
+	// Bytes that weren't valid UTF-8 were replaced with U+FFFD when the state was
+	// written, but a result in the default layout still holds them, so lost material
+	// is recovered from it. Only material that can't be recovered is dropped along
+	// with what was derived from it: planLostKey then replaces a key that lost its
+	// salt or key, and the dropped next_password makes the next apply derive a fresh next key.
+	func(state map[string]any, provider *pbkdf2ProviderData) {
+		if lostMaterial(state, "salt", "key", "cipher_key", "iv") {
+			dk, ok := recoverMaterial(state, provider, "password", "salt", "key", "result")
+			if !ok {
+				clearState(state, "salt", "key", "cipher_key", "iv", "kcv", "result", "sql_statement", "integrity_tag")
+			} else if ivLength, ok := state["iv_length"].(json.Number); ok {
+				n, _ := ivLength.Int64()
+				split := len(dk) - int(n)
+				state["cipher_key"] = string(dk[:split])
+				state["iv"] = string(dk[split:])
+			}
+		}
+		if lostMaterial(state, "next_salt", "next_key") {
+			if _, ok := recoverMaterial(state, provider, "next_password", "next_salt", "next_key", "next_result"); !ok {
+				clearState(state, "next_password", "next_salt", "next_key", "next_result", "integrity_tag")
+			}
+		}
+		for _, name := range []string{"salt", "key", "cipher_key", "iv", "next_salt", "next_key"} {
+			if value, ok := state[name].(string); ok {
+				state[name] = base64.StdEncoding.EncodeToString([]byte(value))
+			}
+		}
+	},
+}
+
+// lostMaterial reports whether any of the raw byte attributes names had bytes replaced when the state was written.
+func lostMaterial(state map[string]any, names ...string) bool {
+	for _, name := range names {
+		if value, ok := state[name].(string); ok && strings.ContainsRune(value, utf8.RuneError) {
+			return true
+		}
+	}
+	return false
+}
+
+// recoverMaterial restores the raw salt and key attributes from a result attribute in the default
+// `<b64 salt>:<b64 key>` layout and returns the key. The key must be what the stored password derives to,
+// or only be of the derived length when the password or pepper is write-only and can't be derived again.
+func recoverMaterial(state map[string]any, provider *pbkdf2ProviderData, password, salt, key, result string) ([]byte, bool) {
+	formatted, _ := state[result].(string)
+	recoveredSalt, recoveredKey, err := pbkdf2kit.ParseSaltKey(formatted)
+	if err != nil {
+		return nil, false
+	}
+	data := upgradedKeyData(state)
+	if len(recoveredKey) != derivedLength(data) {
+		return nil, false
+	}
+	if value, ok := state[password].(string); ok && state["pepper_wo_version"] == nil {
+		dk, _, err := derive(data, provider, value, recoveredSalt)
+		if err != nil || !bytes.Equal(dk, recoveredKey) {
+			return nil, false
+		}
+	}
+	state[salt] = string(recoveredSalt)
+	state[key] = string(recoveredKey)
+	return recoveredKey, true
+}
+
+// upgradedKeyData reads the arguments derive depends on from raw state.
+func upgradedKeyData(state map[string]any) KeyResourceData {
+	return KeyResourceData{
+		Prf:               rawString(state["prf"]),
+		Iterations:        rawInt64(state["iterations"]),
+		KeyLength:         rawInt64(state["key_length"]),
+		CipherKeyLength:   rawInt64(state["cipher_key_length"]),
+		IvLength:          rawInt64(state["iv_length"]),
+		PreHash:           types.BoolValue(state["pre_hash"] == true),
+		Pepper:            rawString(state["pepper"]),
+		PepperMode:        rawString(state["pepper_mode"]),
+		Format:            rawString(state["format"]),
+		FormatPreset:      rawString(state["format_preset"]),
+		PasswordWoVersion: rawString(state["password_wo_version"]),
+	}
+}
+
+func rawString(value any) types.String {
+	if s, ok := value.(string); ok {
+		return types.StringValue(s)
+	}
+	return types.StringNull()
+}
+
+func rawInt64(value any) types.Int64 {
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return types.Int64Value(i)
+		}
+	}
+	return types.Int64Null()
+}
+
+// clearState nulls the attributes names that state has.
+func clearState(state map[string]any, names ...string) {
+	for _, name := range names {
+		if _, ok := state[name]; ok {
+			state[name] = nil
+		}
+	}
+}
+
+// planLostKey replaces a key whose salt the state upgrade couldn't recover, as it can't be derived again.
+// Nothing else leaves the salt null once a key is created or imported.
+func planLostKey(ctx context.Context, state *KeyResourceData, resp *resource.ModifyPlanResponse) {
+	if resp.Diagnostics.HasError() || state == nil || !state.Salt.IsNull() {
+		return
+	}
+	resp.Diagnostics.AddAttributeWarning(path.Root("salt"), "Key Replaced",
+		"The salt or key of this pbkdf2_key held bytes that were lost when it was stored before schema version 2, "+
+			"so it is replaced with a key derived from a fresh salt. Consumers of the previous key have to be updated.")
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("salt"), types.StringUnknown())...)
+	resp.RequiresReplace = append(resp.RequiresReplace, path.Root("salt"))
+}
+
+func (r *KeyResource) UpgradeState(_ context.Context) map[int64]resource.StateUpgrader {
+	upgraders := make(map[int64]resource.StateUpgrader, len(keyStateUpgrades))
+	for version := range keyStateUpgrades {
+		upgraders[int64(version)] = resource.StateUpgrader{
+			StateUpgrader: upgradeKeyState(version, r.provider),
+		}
+	}
+	return upgraders
+}
+
+func upgradeKeyState(version int, provider *pbkdf2ProviderData) func(context.Context, resource.UpgradeStateRequest, *resource.UpgradeStateResponse) {
+	return func(_ context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+		var state map[string]any
+		decoder := json.NewDecoder(bytes.NewReader(req.RawState.JSON))
+		decoder.UseNumber()
+		if err := decoder.Decode(&state); err != nil {
+			resp.Diagnostics.AddError("State Upgrade Error", "Unable to decode prior state: "+err.Error())
+			return
+		}
+
+		for _, upgrade := range keyStateUpgrades[version:] {
+			upgrade(state, provider)
+		}
+
+		data, err := json.Marshal(state)
+		if err != nil {
+			resp.Diagnostics.AddError("State Upgrade Error", "Unable to encode upgraded state: "+err.Error())
+			return
+		}
+		resp.DynamicValue = &tfprotov6.DynamicValue{JSON: data}
+	}
+}
@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+func TestKeyStateUpgradeV0(t *testing.T) {
+	cases := map[string]struct {
+		hashAlgorithm any
+		prf           string
+		alias         string
+	}{
+		"sha512":  {hashAlgorithm: "sha512", prf: "hmac-sha512", alias: "sha512"},
+		"sha256":  {hashAlgorithm: "sha256", prf: "hmac-sha256", alias: "sha256"},
+		"missing": {hashAlgorithm: nil, prf: "hmac-sha256", alias: "sha256"},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := map[string]any{"hash_algorithm": c.hashAlgorithm}
+			keyStateUpgrades[0](state, nil)
+			if state["prf"] != c.prf || state["hash_algorithm"] != c.alias {
+				t.Errorf("got prf %v and hash_algorithm %v", state["prf"], state["hash_algorithm"])
+			}
+		})
+	}
+}
+
+func TestKeyStateUpgradeV1(t *testing.T) {
+	state := map[string]any{"salt": "saltsaltsaltsalt", "key": "\x00\x01key", "iv": nil}
+	keyStateUpgrades[1](state, nil)
+	if state["salt"] != "c2FsdHNhbHRzYWx0c2FsdA==" || state["key"] != "AAFrZXk=" || state["iv"] != nil {
+		t.Errorf("got salt %v, key %v and iv %v", state["salt"], state["key"], state["iv"])
+	}
+}
+
+func TestKeyStateUpgradeV1_lostMaterial(t *testing.T) {
+	const key = "8XlGMksicvsiM5re18mDPJ1M8QW/7+XUUpk6IxTkLzg="
+	lost := func(password string) map[string]any {
+		return map[string]any{
+			"password":      password,
+			"prf":           "hmac-sha256",
+			"iterations":    json.Number("1000"),
+			"format":        pbkdf2kit.DefaultFormat,
+			"salt":          "saltsaltsaltsalt",
+			"key":           "\ufffdXlGMksicvsiM5re18mDPJ1M8QW",
+			"result":        "c2FsdHNhbHRzYWx0c2FsdA==:" + key,
+			"integrity_tag": "00",
+		}
+	}
+
+	// The result holds the key the password derives to, so it is recovered.
+	state := lost("one")
+	keyStateUpgrades[1](state, nil)
+	if state["salt"] != "c2FsdHNhbHRzYWx0c2FsdA==" || state["key"] != key || state["integrity_tag"] != "00" {
+		t.Errorf("got salt %v, key %v and integrity_tag %v", state["salt"], state["key"], state["integrity_tag"])
+	}
+
+	// Another password doesn't derive to it, so nothing is recovered.
+	state = lost("two")
+	keyStateUpgrades[1](state, nil)
+	for _, name := range []string{"salt", "key", "result", "integrity_tag"} {
+		if state[name] != nil {
+			t.Errorf("%s: got %v, want null", name, state[name])
+		}
+	}
+
+	state = map[string]any{"salt": "saltsaltsaltsalt", "key": "key", "next_password": "two", "next_salt": "\ufffd", "next_key": "next", "next_result": "x"}
+	keyStateUpgrades[1](state, nil)
+	for _, name := range []string{"next_password", "next_salt", "next_key", "next_result"} {
+		if state[name] != nil {
+			t.Errorf("%s: got %v, want null", name, state[name])
+		}
+	}
+	if state["salt"] != "c2FsdHNhbHRzYWx0c2FsdA==" || state["key"] != "a2V5" {
+		t.Errorf("got salt %v and key %v", state["salt"], state["key"])
+	}
+}
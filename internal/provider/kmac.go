@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"golang.org/x/crypto/sha3"
+)
+
+// kmacRates are the cSHAKE rates in bytes of each KMAC variant, which bytepad pads the key to.
+var kmacRates = map[string]int{
+	"kmac128": 168,
+	"kmac256": 136,
+}
+
+// kmac computes KMAC128 or KMAC256 as specified by NIST SP 800-185 section 4, with an output of length bytes.
+func kmac(variant string, key, data, customization []byte, length int) []byte {
+	var h sha3.ShakeHash
+	if variant == "kmac128" {
+		h = sha3.NewCShake128([]byte("KMAC"), customization)
+	} else {
+		h = sha3.NewCShake256([]byte("KMAC"), customization)
+	}
+	h.Write(bytepad(encodeString(key), kmacRates[variant]))
+	h.Write(data)
+	h.Write(rightEncode(uint64(length) * 8))
+
+	out := make([]byte, length)
+	h.Read(out)
+	return out
+}
+
+// leftEncode encodes x as its minimal big endian bytes, prefixed with their count.
+func leftEncode(x uint64) []byte {
+	b := bigEndianMinimal(x)
+	return append([]byte{byte(len(b))}, b...)
+}
+
+// rightEncode encodes x as its minimal big endian bytes, followed by their count.
+func rightEncode(x uint64) []byte {
+	b := bigEndianMinimal(x)
+	return append(b, byte(len(b)))
+}
+
+func bigEndianMinimal(x uint64) []byte {
+	n := 1
+	for v := x >> 8; v > 0; v >>= 8 {
+		n++
+	}
+	b := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		b[i] = byte(x)
+		x >>= 8
+	}
+	return b
+}
+
+// encodeString prefixes s with its length in bits.
+func encodeString(s []byte) []byte {
+	return append(leftEncode(uint64(len(s))*8), s...)
+}
+
+// bytepad prefixes x with w and pads it with zeros to a multiple of w bytes.
+func bytepad(x []byte, w int) []byte {
+	padded := append(leftEncode(uint64(w)), x...)
+	if rem := len(padded) % w; rem != 0 {
+		padded = append(padded, make([]byte, w-rem)...)
+	}
+	return padded
+}
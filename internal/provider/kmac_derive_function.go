@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	_ function.Function = &KmacDeriveFunction{}
+)
+
+func NewKmacDeriveFunction() function.Function {
+	return &KmacDeriveFunction{}
+}
+
+type KmacDeriveFunction struct{}
+
+func (f *KmacDeriveFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "kmac_derive"
+}
+
+func (f *KmacDeriveFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Derives a subkey with KMAC.",
+		MarkdownDescription: "Runs the KMAC based KDF of NIST SP 800-108r1 section 4.4, `KMAC(key, context, length, label)` with KMAC128 or KMAC256 from SP 800-185, " +
+			"for deployments standardized on Keccak based constructions. Every input is encoded as SP 800-185 specifies, the output length included, so subkeys of different lengths are unrelated. " +
+			"Returns the subkey base64 encoded.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "key",
+				MarkdownDescription: "The base64 encoded derived key, as exposed by the `key` attribute of `pbkdf2_key`.",
+			},
+			function.StringParameter{
+				Name:                "context",
+				MarkdownDescription: "Information binding the subkey to the parties or session it is derived for, the KMAC input.",
+			},
+			function.StringParameter{
+				Name:                "label",
+				MarkdownDescription: "The purpose of the subkey, the KMAC customization string.",
+			},
+			function.Int64Parameter{
+				Name:                "length",
+				MarkdownDescription: "Length of the subkey in bytes, between 1 and 1024.",
+			},
+			function.StringParameter{
+				Name:                "variant",
+				MarkdownDescription: "`kmac128` or `kmac256`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *KmacDeriveFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var key, kdfContext, label, variant string
+	var length int64
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &key, &kdfContext, &label, &length, &variant))
+	if resp.Error != nil {
+		return
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(key)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "key must be base64 encoded, as the key attribute of pbkdf2_key is")
+		return
+	}
+	if length < 1 || length > 1024 {
+		resp.Error = function.NewArgumentFuncError(3, "length must be between 1 and 1024")
+		return
+	}
+	if _, ok := kmacRates[variant]; !ok {
+		resp.Error = function.NewArgumentFuncError(4, fmt.Sprintf("unsupported variant %q, expected kmac128 or kmac256", variant))
+		return
+	}
+
+	subkey := kmac(variant, secret, []byte(kdfContext), []byte(label), int(length))
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, base64.StdEncoding.EncodeToString(subkey)))
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccKmacDeriveFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::kmac_derive("a2V5", "context", "label", 32, "kmac256")
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "E4bf9NyUkNR1tijoLjWLdnAaYpi2PIY105MxuyHcOnQ="),
+				),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::kmac_derive("a2V5", "context", "label", 32, "kmac512")
+}
+`,
+				ExpectError: regexp.MustCompile(`unsupported variant`),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::kmac_derive("key", "context", "label", 32, "kmac256")
+}
+`,
+				ExpectError: regexp.MustCompile(`key must be base64 encoded`),
+			},
+		},
+	})
+}
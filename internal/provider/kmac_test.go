@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// TestKmac checks the KMAC samples published by NIST for SP 800-185.
+func TestKmac(t *testing.T) {
+	key, _ := hex.DecodeString("404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f")
+	data := []byte{0x00, 0x01, 0x02, 0x03}
+
+	cases := []struct {
+		variant       string
+		customization string
+		length        int
+		expected      string
+	}{
+		{"kmac128", "", 32, "e5780b0d3ea6f7d3a429c5706aa43a00fadbd7d49628839e3187243f456ee14e"},
+		{"kmac128", "My Tagged Application", 32, "3b1fba963cd8b0b59e8c1a6d71888b7143651af8ba0a7070c0979e2811324aa5"},
+		{"kmac256", "My Tagged Application", 64, "20c570c31346f703c9ac36c61c03cb64c3970d0cfc787e9b79599d273a68d2f7" +
+			"f69d4cc3de9d104a351689f27cf6f5951f0103f33f4f24871024d9c27773a8dd"},
+	}
+
+	for _, c := range cases {
+		actual := hex.EncodeToString(kmac(c.variant, key, data, []byte(c.customization), c.length))
+		if actual != c.expected {
+			t.Errorf("%s %q: got %s, want %s", c.variant, c.customization, actual, c.expected)
+		}
+	}
+}
+
+func TestLeftRightEncode(t *testing.T) {
+	if actual := hex.EncodeToString(leftEncode(0)); actual != "0100" {
+		t.Errorf("left_encode(0) = %s", actual)
+	}
+	if actual := hex.EncodeToString(rightEncode(256)); actual != "010002" {
+		t.Errorf("right_encode(256) = %s", actual)
+	}
+	if padded := bytepad([]byte(strings.Repeat("x", 200)), 168); len(padded)%168 != 0 {
+		t.Errorf("bytepad returned %d bytes", len(padded))
+	}
+}
@@ -0,0 +1,43 @@
+package provider
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+// knownAnswers are PBKDF2 test vectors from RFC 6070 (HMAC-SHA1) and RFC 7914 section 11 (HMAC-SHA256).
+var knownAnswers = []struct {
+	source     string
+	prf        string
+	password   string
+	salt       string
+	iterations int
+	expected   string
+}{
+	{"RFC 6070", "hmac-sha1", "password", "salt", 1, "0c60c80f961f0e71f3a9b524af6012062fe037a6"},
+	{"RFC 6070", "hmac-sha1", "password", "salt", 4096, "4b007901b765489abead49d926f721d065a429c1"},
+	{"RFC 6070", "hmac-sha1", "passwordPASSWORDpassword", "saltSALTsaltSALTsaltSALTsaltSALTsalt", 4096, "3d2eec4fe41c849b80c8d83662c0e44a8b291a964cf2f07038"},
+	{"RFC 6070", "hmac-sha1", "pass\x00word", "sa\x00lt", 4096, "56fa6aa75548099dcc37d7f03425e0c3"},
+	{"RFC 7914", "hmac-sha256", "passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+}
+
+// selfTest derives every known answer the way pbkdf2_key derives keys and reports the first mismatch.
+func selfTest() error {
+	for _, vector := range knownAnswers {
+		expected, _ := hex.DecodeString(vector.expected)
+		actual, err := pbkdf2kit.Derive(pbkdf2kit.Params{
+			PRF:        vector.prf,
+			Iterations: vector.iterations,
+			KeyLength:  len(expected),
+		}, vector.password, []byte(vector.salt))
+		if err != nil {
+			return fmt.Errorf("%s vector with %d iterations: %w", vector.source, vector.iterations, err)
+		}
+		if hex.EncodeToString(actual) != vector.expected {
+			return fmt.Errorf("%s vector with %d iterations derived %x, expected %s", vector.source, vector.iterations, actual, vector.expected)
+		}
+	}
+	return nil
+}
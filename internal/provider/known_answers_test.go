@@ -0,0 +1,9 @@
+package provider
+
+import "testing"
+
+func TestSelfTest(t *testing.T) {
+	if err := selfTest(); err != nil {
+		t.Fatal(err)
+	}
+}
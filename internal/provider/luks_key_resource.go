@@ -0,0 +1,188 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"hash"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	_ resource.Resource = &LuksKeyResource{}
+)
+
+// luksHashes are the hash specs cryptsetup accepts for PBKDF2 keyslots, by their `--hash` names.
+var luksHashes = map[string]func() hash.Hash{
+	"sha1":   sha1.New,
+	"sha256": sha256.New,
+	"sha512": sha512.New,
+}
+
+func NewLuksKeyResource() resource.Resource {
+	return &LuksKeyResource{}
+}
+
+type LuksKeyResource struct{}
+
+func (r *LuksKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_luks_key"
+}
+
+func (r *LuksKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Key material of a LUKS keyslot using PBKDF2, derived from a passphrase with the hash, iterations and salt as cryptsetup " +
+			"stores them in the keyslot, so disk encryption bootstrap tooling can be driven from Terraform managed passphrases.",
+
+		Attributes: map[string]schema.Attribute{
+			"passphrase": schema.StringAttribute{
+				MarkdownDescription: "The keyslot passphrase.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"hash": schema.StringAttribute{
+				MarkdownDescription: "The PBKDF2 hash, as `cryptsetup --hash`: `sha1`, `sha256` or `sha512`. Defaults to `sha256`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("sha256"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("sha1", "sha256", "sha512"),
+				},
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of iterations, as `cryptsetup --pbkdf-force-iterations`. At least `1000`, the cryptsetup minimum. Defaults to `1000000`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1000000),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1000),
+				},
+			},
+			"key_size": schema.Int64Attribute{
+				MarkdownDescription: "Size of the key in bits, as `cryptsetup --key-size`, which is the volume key size. Defaults to `512`, for `aes-xts-plain64`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(512),
+				Validators: []validator.Int64{
+					int64validator.OneOf(128, 192, 256, 384, 512),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded 32 byte keyslot salt. Generated randomly when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded keyslot key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"luks2_kdf": schema.StringAttribute{
+				MarkdownDescription: "The `kdf` object of the keyslot in a LUKS2 JSON header, as JSON.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+type LuksKeyResourceData struct {
+	Passphrase types.String `tfsdk:"passphrase"`
+	Hash       types.String `tfsdk:"hash"`
+	Iterations types.Int64  `tfsdk:"iterations"`
+	KeySize    types.Int64  `tfsdk:"key_size"`
+	Salt       types.String `tfsdk:"salt"`
+	Key        types.String `tfsdk:"key"`
+	Luks2Kdf   types.String `tfsdk:"luks2_kdf"`
+}
+
+// luks2Kdf is the kdf object of a LUKS2 keyslot.
+type luks2Kdf struct {
+	Type       string `json:"type"`
+	Hash       string `json:"hash"`
+	Iterations int64  `json:"iterations"`
+	Salt       string `json:"salt"`
+}
+
+// deriveLuksKey fills in salt, key and luks2_kdf of data.
+func deriveLuksKey(data *LuksKeyResourceData, diags *diag.Diagnostics) {
+	var salt []byte
+	if data.Salt.IsUnknown() || data.Salt.IsNull() {
+		var err error
+		salt, err = newSalt(32)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(base64.StdEncoding.EncodeToString(salt))
+	} else if salt = decodeFixed(data.Salt, "salt", 32, diags); diags.HasError() {
+		return
+	}
+
+	key := pbkdf2.Key([]byte(data.Passphrase.ValueString()), salt, int(data.Iterations.ValueInt64()),
+		int(data.KeySize.ValueInt64()/8), luksHashes[data.Hash.ValueString()])
+	data.Key = types.StringValue(base64.StdEncoding.EncodeToString(key))
+
+	kdf, err := json.Marshal(luks2Kdf{
+		Type:       "pbkdf2",
+		Hash:       data.Hash.ValueString(),
+		Iterations: data.Iterations.ValueInt64(),
+		Salt:       data.Salt.ValueString(),
+	})
+	if err != nil {
+		diags.AddError("Encoding Error", err.Error())
+		return
+	}
+	data.Luks2Kdf = types.StringValue(string(kdf))
+}
+
+func (r *LuksKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan LuksKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveLuksKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *LuksKeyResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *LuksKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state LuksKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	deriveLuksKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *LuksKeyResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
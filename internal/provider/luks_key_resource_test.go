@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccLuksKeyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_luks_key" "test" {
+  passphrase = "correct horse"
+  iterations = 1000
+  salt       = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_luks_key.test", "key", "Z9E+pkdhhD7XikB9ZtCvDdt0XzBx7kc3CPyJJQpk0m7x/IRtFLLjYs7WIGxjWDgTWwTw4JJPBiwkuHiTutjufg=="),
+					resource.TestCheckResourceAttr("pbkdf2_luks_key.test", "luks2_kdf", `{"type":"pbkdf2","hash":"sha256","iterations":1000,"salt":"AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="}`),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_luks_key" "test" {
+  passphrase = "correct horse"
+  hash       = "sha1"
+  iterations = 2000
+  key_size   = 256
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The salt is kept when it is no longer configured.
+					resource.TestCheckResourceAttr("pbkdf2_luks_key.test", "salt", "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="),
+					resource.TestCheckResourceAttr("pbkdf2_luks_key.test", "key", "o44w5UhasvF7FFhFdlaclqRq0U9lcL/gVz38w4elWok="),
+				),
+			},
+		},
+	})
+}
+
+func TestAccLuksKeyResource_invalidSalt(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_luks_key" "test" {
+  passphrase = "correct horse"
+  iterations = 1000
+  salt       = "c2hvcnQ="
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid salt`),
+			},
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ function.Function = &NeedsRehashFunction{}
+)
+
+func NewNeedsRehashFunction() function.Function {
+	return &NeedsRehashFunction{}
+}
+
+type NeedsRehashFunction struct{}
+
+type rehashPolicy struct {
+	MinIterations types.Int64  `tfsdk:"min_iterations"`
+	HashAlgorithm types.String `tfsdk:"hash_algorithm"`
+	MinSaltLength types.Int64  `tfsdk:"min_salt_length"`
+}
+
+func (f *NeedsRehashFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "needs_rehash"
+}
+
+func (f *NeedsRehashFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Checks whether a formatted hash falls short of a parameter policy.",
+		MarkdownDescription: "Parses a PHC, passlib or Django style PBKDF2 hash and returns `true` when it does not meet `policy`, i.e. when it should be rehashed. Policy attributes set to `null` are not checked.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "hash",
+				MarkdownDescription: "The formatted hash, e.g. `$pbkdf2-sha256$29000$<salt>$<key>` or `pbkdf2_sha256$600000$<salt>$<key>`.",
+			},
+			function.ObjectParameter{
+				Name:                "policy",
+				MarkdownDescription: "The required parameters: `min_iterations`, `hash_algorithm` and `min_salt_length`.",
+				AttributeTypes: map[string]attr.Type{
+					"min_iterations":  types.Int64Type,
+					"hash_algorithm":  types.StringType,
+					"min_salt_length": types.Int64Type,
+				},
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *NeedsRehashFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var hash string
+	var policy rehashPolicy
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &hash, &policy))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := pbkdf2kit.ParseHash(hash)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, "Invalid hash: "+err.Error())
+		return
+	}
+
+	needsRehash := (!policy.MinIterations.IsNull() && int64(parsed.Iterations) < policy.MinIterations.ValueInt64()) ||
+		(!policy.HashAlgorithm.IsNull() && parsed.HashAlgorithm != policy.HashAlgorithm.ValueString()) ||
+		(!policy.MinSaltLength.IsNull() && int64(len(parsed.Salt)) < policy.MinSaltLength.ValueInt64())
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, needsRehash))
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccNeedsRehashFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNeedsRehashFunctionConfig(600000),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "true"),
+				),
+			},
+			{
+				Config: testAccNeedsRehashFunctionConfig(29000),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("test", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccNeedsRehashFunctionConfig(minIterations int) string {
+	return fmt.Sprintf(`
+output "test" {
+  value = provider::pbkdf2::needs_rehash("$pbkdf2-sha256$29000$N2YuJ.R4T2XsvVZ2LgWAsA$mxqhdZUvUUNyHMQ8SdjOP6SyqXCQHT7zsAKf6JjtxVM", {
+    min_iterations  = %[1]d
+    hash_algorithm  = "sha256"
+    min_salt_length = null
+  })
+}
+`, minIterations)
+}
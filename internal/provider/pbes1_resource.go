@@ -0,0 +1,198 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource                   = &Pbes1Resource{}
+	_ resource.ResourceWithValidateConfig = &Pbes1Resource{}
+)
+
+func NewPbes1Resource() resource.Resource {
+	return &Pbes1Resource{}
+}
+
+type Pbes1Resource struct{}
+
+// pbes1SchemeNames lists the keys of pbes1Schemes in a stable order.
+func pbes1SchemeNames() []string {
+	names := make([]string, 0, len(pbes1Schemes))
+	for name := range pbes1Schemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Pbes1Resource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_pbes1"
+}
+
+func (r *Pbes1Resource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Legacy PKCS #5 v1.5 password-based encryption, PBES1: a DES or RC2 key and IV derived with PBKDF1 over MD5 or SHA-1, " +
+			"optionally encrypting a value, to generate or validate configuration blobs of old middleware. " +
+			"**Insecure**: the derived key is 56 or 64 bits and the ciphers are broken. Only use it to interoperate, and acknowledge that with `allow_legacy`.",
+
+		Attributes: map[string]schema.Attribute{
+			"allow_legacy": schema.BoolAttribute{
+				MarkdownDescription: "Must be `true`, to acknowledge that the scheme is insecure.",
+				Required:            true,
+			},
+			"scheme": schema.StringAttribute{
+				MarkdownDescription: "The scheme by its PKCS #5 name: " + markdownList(pbes1SchemeNames()) + ".",
+				Required:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbes1SchemeNames()...),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to derive from.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded 8 byte salt. Generated when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-fA-F]{16}$`), "must be 8 hex encoded bytes"),
+				},
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of iterations. Defaults to `1000`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(1000),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"plaintext": schema.StringAttribute{
+				MarkdownDescription: "Value to encrypt into `ciphertext`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded 8 byte cipher key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"iv": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded 8 byte IV.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"ciphertext": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded encryption of `plaintext` in CBC mode with PKCS #5 padding. Null unless `plaintext` is set.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+type Pbes1ResourceData struct {
+	AllowLegacy types.Bool   `tfsdk:"allow_legacy"`
+	Scheme      types.String `tfsdk:"scheme"`
+	Password    types.String `tfsdk:"password"`
+	Salt        types.String `tfsdk:"salt"`
+	Iterations  types.Int64  `tfsdk:"iterations"`
+	Plaintext   types.String `tfsdk:"plaintext"`
+	Key         types.String `tfsdk:"key"`
+	Iv          types.String `tfsdk:"iv"`
+	Ciphertext  types.String `tfsdk:"ciphertext"`
+}
+
+func (r *Pbes1Resource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var allowLegacy types.Bool
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("allow_legacy"), &allowLegacy)...)
+	if resp.Diagnostics.HasError() || allowLegacy.IsUnknown() {
+		return
+	}
+	if !allowLegacy.ValueBool() {
+		resp.Diagnostics.AddAttributeError(path.Root("allow_legacy"), "Legacy Scheme Not Allowed",
+			"PBES1 is insecure and only meant for interoperating with old software. Set allow_legacy = true to use it anyway.")
+	}
+}
+
+// derivePbes1 fills in salt, key, iv and ciphertext of data.
+func derivePbes1(data *Pbes1ResourceData, diags *diag.Diagnostics) {
+	data.Salt = generatedHex(data.Salt, 8, diags)
+	if diags.HasError() {
+		return
+	}
+	salt, err := hex.DecodeString(data.Salt.ValueString())
+	if err != nil {
+		diags.AddAttributeError(path.Root("salt"), "Invalid Salt", "The salt is not valid hex.")
+		return
+	}
+
+	scheme := pbes1Schemes[data.Scheme.ValueString()]
+	dk := pbkdf1(scheme.hash, []byte(data.Password.ValueString()), salt, int(data.Iterations.ValueInt64()))
+	data.Key = types.StringValue(hex.EncodeToString(dk[:8]))
+	data.Iv = types.StringValue(hex.EncodeToString(dk[8:16]))
+
+	data.Ciphertext = types.StringNull()
+	if !data.Plaintext.IsNull() {
+		ciphertext, err := pbes1Encrypt(scheme, dk, []byte(data.Plaintext.ValueString()))
+		if err != nil {
+			diags.AddError("Encryption Error", err.Error())
+			return
+		}
+		data.Ciphertext = types.StringValue(base64.StdEncoding.EncodeToString(ciphertext))
+	}
+}
+
+func (r *Pbes1Resource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan Pbes1ResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	derivePbes1(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Pbes1Resource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *Pbes1Resource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state Pbes1ResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	derivePbes1(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Pbes1Resource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
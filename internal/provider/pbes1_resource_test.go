@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccPbes1Resource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_pbes1" "test" {
+  allow_legacy = true
+  scheme       = "pbeWithMD5AndDES-CBC"
+  password     = "password"
+  salt         = "0102030405060708"
+  plaintext    = "hello legacy middleware"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_pbes1.test", "key", "2699a412f5427519"),
+					resource.TestCheckResourceAttr("pbkdf2_pbes1.test", "iv", "88e26bb589323805"),
+					resource.TestCheckResourceAttr("pbkdf2_pbes1.test", "ciphertext", "KZ5gn6PDs0PnEu1jTrE3LwAvWcM5FcDa"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_pbes1" "test" {
+  allow_legacy = true
+  scheme       = "pbeWithSHA1AndRC2-CBC"
+  password     = "password"
+  plaintext    = "hello legacy middleware"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The salt is kept when it is no longer configured.
+					resource.TestCheckResourceAttr("pbkdf2_pbes1.test", "salt", "0102030405060708"),
+					resource.TestCheckResourceAttr("pbkdf2_pbes1.test", "ciphertext", "SPzZJele6WoJtsQUGIx5DX6am20rsfja"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPbes1Resource_notAllowed(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_pbes1" "test" {
+  allow_legacy = false
+  scheme       = "pbeWithMD5AndDES-CBC"
+  password     = "password"
+}
+`,
+				ExpectError: regexp.MustCompile(`Legacy Scheme Not Allowed`),
+			},
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"testing"
+)
+
+func TestRC2(t *testing.T) {
+	// RFC 2268 section 5 with an effective key length of 64 bits.
+	block, _ := newRC2(make([]byte, 8))
+	dst := make([]byte, 8)
+	block.Encrypt(dst, make([]byte, 8))
+	if actual := hex.EncodeToString(dst); actual != "ebb773f993278eff" {
+		t.Errorf("got %s", actual)
+	}
+
+	key, _ := hex.DecodeString("ffffffffffffffff")
+	block, _ = newRC2(key)
+	block.Encrypt(dst, key)
+	if actual := hex.EncodeToString(dst); actual != "278b27e42e2f0d49" {
+		t.Errorf("got %s", actual)
+	}
+}
+
+func TestPBES1(t *testing.T) {
+	salt, _ := hex.DecodeString("0102030405060708")
+	cases := []struct {
+		scheme     string
+		dk         string
+		ciphertext string
+	}{
+		{"pbeWithMD5AndDES-CBC", "2699a412f542751988e26bb589323805", "KZ5gn6PDs0PnEu1jTrE3LwAvWcM5FcDa"},
+		{"pbeWithSHA1AndDES-CBC", "87dfb26daf45f63f1ac78f033186d5ea", "QLiFQQmcgt53BBCoxLgBN/d/e1Lixv+U"},
+		{"pbeWithSHA1AndRC2-CBC", "87dfb26daf45f63f1ac78f033186d5ea", "SPzZJele6WoJtsQUGIx5DX6am20rsfja"},
+	}
+	for _, c := range cases {
+		scheme := pbes1Schemes[c.scheme]
+		dk := pbkdf1(scheme.hash, []byte("password"), salt, 1000)
+		if actual := hex.EncodeToString(dk); actual != c.dk {
+			t.Errorf("%s: derived %s", c.scheme, actual)
+		}
+		ciphertext, err := pbes1Encrypt(scheme, dk, []byte("hello legacy middleware"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := base64.StdEncoding.EncodeToString(ciphertext); actual != c.ciphertext {
+			t.Errorf("%s: encrypted to %s", c.scheme, actual)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runPepperCommand runs args and returns its standard output without the trailing newline, so the pepper can be
+// unsealed from a TPM or read from a PKCS #11 token by their own tools instead of being configured in plain text.
+// The output is never included in the error, as it may hold part of the pepper.
+func runPepperCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) == 0 || args[0] == "" {
+		return "", errors.New("no command given")
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+	pepper := strings.TrimRight(stdout.String(), "\r\n")
+	if pepper == "" {
+		return "", fmt.Errorf("%s printed no pepper", args[0])
+	}
+	return pepper, nil
+}
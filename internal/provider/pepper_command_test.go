@@ -0,0 +1,61 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestRunPepperCommand(t *testing.T) {
+	pepper, err := runPepperCommand(context.Background(), []string{"printf", "unsealed\n"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pepper != "unsealed" {
+		t.Errorf("got %q, expected %q", pepper, "unsealed")
+	}
+
+	if _, err := runPepperCommand(context.Background(), []string{"sh", "-c", "echo secret; exit 1"}); err == nil || strings.Contains(err.Error(), "secret") {
+		t.Errorf("expected an error without the output, got %v", err)
+	}
+	if _, err := runPepperCommand(context.Background(), []string{"true"}); err == nil {
+		t.Error("expected an error for empty output")
+	}
+	if _, err := runPepperCommand(context.Background(), nil); err == nil {
+		t.Error("expected an error without a command")
+	}
+}
+
+func TestAccProvider_pepperCommandConflict(t *testing.T) {
+	marker := filepath.Join(t.TempDir(), "ran")
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(`
+provider "pbkdf2" {
+  pepper         = "configured"
+  pepper_command = ["sh", "-c", "touch %s && echo unsealed"]
+}
+
+resource "pbkdf2_key" "test" {
+  password   = "one"
+  iterations = 1000
+}
+`, marker),
+				ExpectError: regexp.MustCompile(`Conflicting Pepper`),
+			},
+		},
+	})
+	// A misconfigured provider doesn't run the command.
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("pepper_command ran despite the conflicting pepper")
+	}
+}
@@ -0,0 +1,64 @@
+package provider
+
+import (
+	"sync"
+	"time"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+// planCost sums the estimated derivation time of the keys planned for (re)computation by one provider
+// instance, so a single warning can be emitted when the apply as a whole gets expensive.
+type planCost struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	total     time.Duration
+	warned    bool
+	rates     map[string]int64
+	calibrate func(p pbkdf2kit.PRF) int64
+}
+
+func newPlanCost(threshold time.Duration) *planCost {
+	return &planCost{
+		threshold: threshold,
+		rates:     map[string]int64{},
+		calibrate: func(p pbkdf2kit.PRF) int64 {
+			return calibrateIterations(time.Second, p)
+		},
+	}
+}
+
+// estimate returns how long derivations of the given iterations take with the named PRF on this host.
+// The rate of each PRF is measured once and reused.
+func (c *planCost) estimate(prf string, iterations int64, derivations int) time.Duration {
+	p, ok := pbkdf2kit.LookupPRF(prf)
+	if !ok || iterations <= 0 {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rate, ok := c.rates[p.Name]
+	if !ok {
+		rate = c.calibrate(p)
+		c.rates[p.Name] = rate
+	}
+	return time.Duration(float64(iterations) / float64(rate) * float64(time.Second) * float64(derivations))
+}
+
+// add records the cost of one resource and returns the running total, and whether this is the first
+// time the total exceeds the threshold.
+func (c *planCost) add(cost time.Duration) (time.Duration, bool) {
+	if c == nil || c.threshold <= 0 {
+		return 0, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.total += cost
+	if c.warned || c.total <= c.threshold {
+		return c.total, false
+	}
+	c.warned = true
+	return c.total, true
+}
@@ -0,0 +1,44 @@
+package provider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+func TestPlanCost(t *testing.T) {
+	cost := newPlanCost(3 * time.Second)
+	calibrations := 0
+	cost.calibrate = func(pbkdf2kit.PRF) int64 {
+		calibrations++
+		return 100000
+	}
+
+	if got := cost.estimate("sha256", 200000, 1); got != 2*time.Second {
+		t.Errorf("estimate = %s, want 2s", got)
+	}
+	if got := cost.estimate("sha256", 100000, 3); got != 3*time.Second {
+		t.Errorf("estimate = %s, want 3s", got)
+	}
+	if calibrations != 1 {
+		t.Errorf("calibrated %d times, want once per prf", calibrations)
+	}
+	if got := cost.estimate("md5", 100000, 1); got != 0 {
+		t.Errorf("estimate for an unknown prf = %s, want 0", got)
+	}
+
+	if _, exceeded := cost.add(2 * time.Second); exceeded {
+		t.Error("warned below the threshold")
+	}
+	if total, exceeded := cost.add(2 * time.Second); !exceeded || total != 4*time.Second {
+		t.Errorf("add = %s, %t, want 4s, true", total, exceeded)
+	}
+	if _, exceeded := cost.add(2 * time.Second); exceeded {
+		t.Error("warned more than once")
+	}
+
+	if _, exceeded := newPlanCost(0).add(time.Hour); exceeded {
+		t.Error("warned with the check disabled")
+	}
+}
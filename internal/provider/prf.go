@@ -0,0 +1,54 @@
+package provider
+
+import (
+	"hash"
+	"strings"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+)
+
+func getHashAlgorithm(hashFunc string) (int, func() hash.Hash) {
+	p := lookupPRF(hashFunc)
+	return p.Size, p.Hash
+}
+
+// lookupPRF finds a PRF by name, falling back to the default PRF when the name is unset. Configured names
+// are checked by the schema, so they are never replaced silently.
+func lookupPRF(name string) pbkdf2kit.PRF {
+	p, ok := pbkdf2kit.LookupPRF(name)
+	if !ok {
+		p, _ = pbkdf2kit.LookupPRF(pbkdf2kit.DefaultPRF)
+	}
+	return p
+}
+
+// prfAliases lists the bare hash names accepted by hash_algorithm.
+func prfAliases() []string {
+	var aliases []string
+	for _, p := range pbkdf2kit.PRFs {
+		if p.Alias != "" {
+			aliases = append(aliases, p.Alias)
+		}
+	}
+	return aliases
+}
+
+// hmacPRFNames lists the PRFs that are an HMAC over a hash, for uses that need the MAC itself.
+func hmacPRFNames() []string {
+	var names []string
+	for _, p := range pbkdf2kit.PRFs {
+		if p.Keyed == nil {
+			names = append(names, p.Name)
+		}
+	}
+	return names
+}
+
+// markdownList renders names as a comma separated list of code spans.
+func markdownList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "`" + name + "`"
+	}
+	return strings.Join(quoted, ", ")
+}
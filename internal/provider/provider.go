@@ -2,19 +2,195 @@ package provider
 
 import (
 	"context"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
 
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/provider"
 	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 )
 
-var _ provider.Provider = &pbkdf2Provider{}
+var (
+	_ provider.Provider                       = &pbkdf2Provider{}
+	_ provider.ProviderWithFunctions          = &pbkdf2Provider{}
+	_ provider.ProviderWithEphemeralResources = &pbkdf2Provider{}
+)
 
 type pbkdf2Provider struct {
 	version string
 }
 
+type pbkdf2ProviderModel struct {
+	MinPasswordScore           types.Int64  `tfsdk:"min_password_score"`
+	IntegrityKey               types.String `tfsdk:"integrity_key"`
+	FingerprintKey             types.String `tfsdk:"fingerprint_key"`
+	PlaceholderPasswords       types.List   `tfsdk:"placeholder_passwords"`
+	PlaceholderPasswordPattern types.String `tfsdk:"placeholder_password_pattern"`
+	RejectPlaceholderPasswords types.Bool   `tfsdk:"reject_placeholder_passwords"`
+	RedactErrors               types.Bool   `tfsdk:"redact_errors"`
+	Pepper                     types.String `tfsdk:"pepper"`
+	PepperCommand              types.List   `tfsdk:"pepper_command"`
+	DefaultIterations          types.Int64  `tfsdk:"default_iterations"`
+	DefaultPRF                 types.String `tfsdk:"default_prf"`
+	DefaultSaltLength          types.Int64  `tfsdk:"default_salt_length"`
+	DefaultFormat              types.String `tfsdk:"default_format"`
+	FipsMode                   types.Bool   `tfsdk:"fips_mode"`
+	DerivationContext          types.String `tfsdk:"derivation_context"`
+	SelfTest                   types.Bool   `tfsdk:"self_test"`
+	PlanCostWarningMs          types.Int64  `tfsdk:"plan_cost_warning_ms"`
+	VerifyOnRefresh            types.Bool   `tfsdk:"verify_on_refresh"`
+	Parallelism                types.Int64  `tfsdk:"parallelism"`
+}
+
+// pbkdf2ProviderData is handed to resources and data sources on Configure.
+type pbkdf2ProviderData struct {
+	Version                    string
+	MinPasswordScore           int
+	IntegrityKey               string
+	FingerprintKey             string
+	PlaceholderPasswords       []string
+	PlaceholderPasswordPattern *regexp.Regexp
+	RejectPlaceholderPasswords bool
+	RedactErrors               bool
+	Pepper                     string
+	DefaultIterations          int64
+	DefaultPRF                 string
+	DefaultSaltLength          int64
+	DefaultFormat              string
+	FipsMode                   bool
+	DerivationContext          string
+	VerifyOnRefresh            bool
+	Derivations                chan struct{}
+	PlanCost                   *planCost
+	SeenMaterial               *seenMaterial
+}
+
+// redactErrors reports whether diagnostics must not echo anything derived from user data.
+func (d *pbkdf2ProviderData) redactErrors() bool {
+	return d != nil && d.RedactErrors
+}
+
+// pepper returns the configured pepper, or an empty string when there is none.
+func (d *pbkdf2ProviderData) pepper() string {
+	if d == nil {
+		return ""
+	}
+	return d.Pepper
+}
+
+// derivationContext returns the configured derivation context, or an empty string when there is none.
+func (d *pbkdf2ProviderData) derivationContext() string {
+	if d == nil {
+		return ""
+	}
+	return d.DerivationContext
+}
+
+// defaultIterations returns the iteration count for keys that don't set one.
+func (d *pbkdf2ProviderData) defaultIterations() int64 {
+	if d == nil || d.DefaultIterations == 0 {
+		return 100000
+	}
+	return d.DefaultIterations
+}
+
+// defaultPRF returns the PRF for keys that set neither prf nor hash_algorithm.
+func (d *pbkdf2ProviderData) defaultPRF() string {
+	if d == nil || d.DefaultPRF == "" {
+		return pbkdf2kit.DefaultPRF
+	}
+	return d.DefaultPRF
+}
+
+// defaultSaltLength returns the salt length for keys that don't set one.
+func (d *pbkdf2ProviderData) defaultSaltLength() int64 {
+	if d == nil || d.DefaultSaltLength == 0 {
+		return 16
+	}
+	return d.DefaultSaltLength
+}
+
+// defaultFormat returns the format template for keys that set neither format nor format_file.
+func (d *pbkdf2ProviderData) defaultFormat() string {
+	if d == nil || d.DefaultFormat == "" {
+		return pbkdf2kit.DefaultFormat
+	}
+	return d.DefaultFormat
+}
+
+// fipsMode reports whether key parameters must meet NIST SP 800-132.
+func (d *pbkdf2ProviderData) fipsMode() bool {
+	return d != nil && d.FipsMode
+}
+
+// verifyOnRefresh reports whether pbkdf2_key material is re-derived and compared on refresh.
+func (d *pbkdf2ProviderData) verifyOnRefresh() bool {
+	return d != nil && d.VerifyOnRefresh
+}
+
+// acquireDerivation blocks until fewer than parallelism derivations run and returns the function ending this one.
+// Derivations holds a token per running derivation, and is nil when they are not limited.
+func (d *pbkdf2ProviderData) acquireDerivation() func() {
+	if d == nil || d.Derivations == nil {
+		return func() {}
+	}
+	d.Derivations <- struct{}{}
+	return func() { <-d.Derivations }
+}
+
+// minIterations returns the lowest iteration count keys may use.
+func (d *pbkdf2ProviderData) minIterations() int64 {
+	if d.fipsMode() {
+		return 1000
+	}
+	return 1
+}
+
+// planCost returns the accumulator of planned derivation time, or nil when there is none.
+func (d *pbkdf2ProviderData) planCost() *planCost {
+	if d == nil {
+		return nil
+	}
+	return d.PlanCost
+}
+
+// seenMaterial returns the salts and keys handled so far, or nil when they aren't tracked.
+func (d *pbkdf2ProviderData) seenMaterial() *seenMaterial {
+	if d == nil {
+		return nil
+	}
+	return d.SeenMaterial
+}
+
+// defaultPlaceholderPasswords are flagged unless placeholder_passwords is configured.
+var defaultPlaceholderPasswords = []string{
+	"admin",
+	"changeit",
+	"changeme",
+	"change-me",
+	"change_me",
+	"default",
+	"example",
+	"letmein",
+	"password",
+	"password1",
+	"password123",
+	"placeholder",
+	"secret",
+	"todo",
+}
+
 func (p *pbkdf2Provider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
 	resp.TypeName = "pbkdf2"
 	resp.Version = p.version
@@ -23,20 +199,268 @@ func (p *pbkdf2Provider) Metadata(_ context.Context, _ provider.MetadataRequest,
 func (p *pbkdf2Provider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
 	resp.Schema = schema.Schema{
 		MarkdownDescription: "This is for using PBKDF2 for deriving keys.",
+
+		Attributes: map[string]schema.Attribute{
+			"min_password_score": schema.Int64Attribute{
+				MarkdownDescription: "Minimum zxcvbn strength score (0-4) below which a plan warning is emitted for guessable passwords. The check is off unless set; `2` flags passwords that fall to an online attack within days.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(0, 4),
+				},
+			},
+			"integrity_key": schema.StringAttribute{
+				MarkdownDescription: "Secret used to tag the stored salt, key and derivation parameters of each `pbkdf2_key` with an HMAC that is verified on refresh, so tampered or corrupted state is detected. Keys created without it are not checked.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"fingerprint_key": schema.StringAttribute{
+				MarkdownDescription: "Secret keying the `pbkdf2_fingerprint` data source. Share it between workspaces whose fingerprints should be comparable, and keep it as secret as the passwords: with the key, a fingerprint can be guessed against as fast as an unsalted hash.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"placeholder_passwords": schema.ListAttribute{
+				MarkdownDescription: "Passwords flagged as placeholders during plan, compared case-insensitively. Defaults to a list of common ones such as `changeme` and `password123`; set to `[]` to disable the check.",
+				ElementType:         types.StringType,
+				Optional:            true,
+			},
+			"placeholder_password_pattern": schema.StringAttribute{
+				MarkdownDescription: "Regular expression; passwords matching it are flagged as placeholders in addition to `placeholder_passwords`.",
+				Optional:            true,
+			},
+			"reject_placeholder_passwords": schema.BoolAttribute{
+				MarkdownDescription: "Fail the plan on placeholder passwords instead of warning about them, e.g. in CI pipelines that deploy to production. Defaults to `false`.",
+				Optional:            true,
+			},
+			"pepper": schema.StringAttribute{
+				MarkdownDescription: "Secret mixed into every `pbkdf2_key` password before derivation, kept out of the hashes so a leaked hash alone can't be cracked. How it is applied is chosen per key with `pepper_mode`. Can also be set with the `PBKDF2_PEPPER` environment variable.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"pepper_command": schema.ListAttribute{
+				MarkdownDescription: "Command and arguments run when the provider is configured, whose output, less the trailing newline, is used as `pepper`, " +
+					"so the pepper can be unsealed from a TPM 2.0, such as with `[\"tpm2_unseal\", \"-c\", \"0x81000001\"]`, or read from a PKCS #11 token with `pkcs11-tool --read-object`, " +
+					"and never appears in configuration or CI variables. Conflicts with `pepper` and `PBKDF2_PEPPER`.",
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"derivation_context": schema.StringAttribute{
+				MarkdownDescription: "Label mixed into the salt of every `pbkdf2_key` derivation, such as `terraform.workspace` or an environment name, so the same password yields unrelated keys in each context. " +
+					"Like `pepper`, it is not part of `result`, so hashes derived with it only verify where the context is applied as well, " +
+					"and keys can't combine it with `format_preset` or a `format` other than the default. Changing it leaves existing keys as they are, unless `verify_on_refresh` replaces them. " +
+					"Can also be set with the `PBKDF2_DERIVATION_CONTEXT` environment variable.",
+				Optional: true,
+			},
+			"default_iterations": schema.Int64Attribute{
+				MarkdownDescription: "Iterations of `pbkdf2_key` resources that set neither `iterations` nor `target_duration_ms`. Raising it re-derives those keys on the next apply. Defaults to `100000`. Can also be set with the `PBKDF2_DEFAULT_ITERATIONS` environment variable.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"default_prf": schema.StringAttribute{
+				MarkdownDescription: "PRF of `pbkdf2_key` resources that set neither `prf` nor `hash_algorithm`: " + markdownList(pbkdf2kit.PRFNames()) + ". " +
+					"Changing it generates new salts for those keys unless their `replace_on` leaves out `prf`. Defaults to `" + pbkdf2kit.DefaultPRF + "`.",
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PRFNames()...),
+				},
+			},
+			"default_salt_length": schema.Int64Attribute{
+				MarkdownDescription: "Salt length of `pbkdf2_key` resources that don't set `salt_length`. Changing it generates new salts for those keys. Defaults to `16`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"default_format": schema.StringAttribute{
+				MarkdownDescription: "Format template of `pbkdf2_key` resources that set neither `format` nor `format_file`. Changing it re-renders their `result`. Defaults to `<b64 salt>:<b64 key>`.",
+				Optional:            true,
+			},
+			"fips_mode": schema.BoolAttribute{
+				MarkdownDescription: "Reject `pbkdf2_key` parameters outside NIST SP 800-132: keyed BLAKE2b and Streebog PRFs, salts shorter than 16 bytes, fewer than 1000 iterations and keys shorter than 14 bytes (112 bits). Defaults to `false`. Can also be set with the `PBKDF2_FIPS_MODE` environment variable.",
+				Optional:            true,
+			},
+			"parallelism": schema.Int64Attribute{
+				MarkdownDescription: "Maximum number of `pbkdf2_key` derivations run at once, so expensive keys don't occupy every core while Terraform applies other resources in parallel. Unlimited by default. " +
+					"Can also be set with the `PBKDF2_PARALLELISM` environment variable.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"self_test": schema.BoolAttribute{
+				MarkdownDescription: "Check the PBKDF2 implementation against the RFC 6070 and RFC 7914 test vectors when the provider is configured, and fail if the host derives anything else. Always done in `fips_mode`. Defaults to `false`.",
+				Optional:            true,
+			},
+			"plan_cost_warning_ms": schema.Int64Attribute{
+				MarkdownDescription: "Warn during plan when the `pbkdf2_key` resources about to be derived or re-derived are estimated to take longer than this many milliseconds in total on this host, " +
+					"so reviewers know the apply will keep the CPU busy before it gets to anything else. The check is off unless set, e.g. to `60000` to warn about applies longer than a minute.",
+				Optional: true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+			"verify_on_refresh": schema.BoolAttribute{
+				MarkdownDescription: "Re-derive every `pbkdf2_key` on refresh and compare it with its stored salt, key and result, which costs a full derivation per key, and one more per `next_password`, on every plan. " +
+					"A key that no longer matches, because its state was edited or corrupted or the `pepper` or `derivation_context` changed, is removed from state with a warning, so the next apply creates it anew; " +
+					"a key with `deletion_protection` fails the refresh instead. Defaults to `false`.",
+				Optional: true,
+			},
+			"redact_errors": schema.BoolAttribute{
+				MarkdownDescription: "Strict mode for diagnostics: errors from the `format` template are cut down to the failing position, without the offending expression or the underlying message, so nothing evaluated from salts, keys or passwords can be echoed. Defaults to `false`.",
+				Optional:            true,
+			},
+		},
 	}
 }
 
 func (p *pbkdf2Provider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config pbkdf2ProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	data := &pbkdf2ProviderData{
+		Version:          p.version,
+		MinPasswordScore: int(config.MinPasswordScore.ValueInt64()),
+	}
+	data.IntegrityKey = config.IntegrityKey.ValueString()
+	data.FingerprintKey = config.FingerprintKey.ValueString()
+	data.RedactErrors = config.RedactErrors.ValueBool()
+	data.Pepper = config.Pepper.ValueString()
+	if config.Pepper.IsNull() {
+		data.Pepper = os.Getenv("PBKDF2_PEPPER")
+	}
+	if !config.PepperCommand.IsNull() {
+		if data.Pepper != "" {
+			resp.Diagnostics.AddAttributeError(path.Root("pepper_command"), "Conflicting Pepper",
+				"pepper_command can't be combined with pepper or the PBKDF2_PEPPER environment variable.")
+			return
+		}
+		var args []string
+		resp.Diagnostics.Append(config.PepperCommand.ElementsAs(ctx, &args, false)...)
+		pepper, err := runPepperCommand(ctx, args)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("pepper_command"), "Pepper Command Failed", err.Error())
+		}
+		data.Pepper = pepper
+	}
+	data.VerifyOnRefresh = config.VerifyOnRefresh.ValueBool()
+	data.DerivationContext = config.DerivationContext.ValueString()
+	if config.DerivationContext.IsNull() {
+		data.DerivationContext = os.Getenv("PBKDF2_DERIVATION_CONTEXT")
+	}
+	data.DefaultIterations = config.DefaultIterations.ValueInt64()
+	if v := os.Getenv("PBKDF2_DEFAULT_ITERATIONS"); config.DefaultIterations.IsNull() && v != "" {
+		iterations, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || iterations < 1 {
+			resp.Diagnostics.AddError("Invalid Environment Variable", "PBKDF2_DEFAULT_ITERATIONS must be a positive integer.")
+		}
+		data.DefaultIterations = iterations
+	}
+	data.DefaultPRF = config.DefaultPRF.ValueString()
+	data.DefaultSaltLength = config.DefaultSaltLength.ValueInt64()
+	data.DefaultFormat = config.DefaultFormat.ValueString()
+	data.FipsMode = config.FipsMode.ValueBool()
+	if v := os.Getenv("PBKDF2_FIPS_MODE"); config.FipsMode.IsNull() && v != "" {
+		fipsMode, err := strconv.ParseBool(v)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid Environment Variable", "PBKDF2_FIPS_MODE must be true or false.")
+		}
+		data.FipsMode = fipsMode
+	}
+
+	parallelism := config.Parallelism.ValueInt64()
+	if v := os.Getenv("PBKDF2_PARALLELISM"); config.Parallelism.IsNull() && v != "" {
+		var err error
+		parallelism, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || parallelism < 1 {
+			resp.Diagnostics.AddError("Invalid Environment Variable", "PBKDF2_PARALLELISM must be a positive integer.")
+		}
+	}
+	if parallelism > 0 {
+		data.Derivations = make(chan struct{}, parallelism)
+	}
+
+	data.SeenMaterial = newSeenMaterial()
+	if !config.PlanCostWarningMs.IsNull() {
+		data.PlanCost = newPlanCost(time.Duration(config.PlanCostWarningMs.ValueInt64()) * time.Millisecond)
+	}
+
+	if config.SelfTest.ValueBool() || data.FipsMode {
+		if err := selfTest(); err != nil {
+			resp.Diagnostics.AddError("Self-Test Failed",
+				"PBKDF2 returned a wrong result for a known answer test, so keys derived on this host can't be trusted: "+err.Error()+".")
+		}
+	}
 
+	data.RejectPlaceholderPasswords = config.RejectPlaceholderPasswords.ValueBool()
+	data.PlaceholderPasswords = defaultPlaceholderPasswords
+	if !config.PlaceholderPasswords.IsNull() {
+		resp.Diagnostics.Append(config.PlaceholderPasswords.ElementsAs(ctx, &data.PlaceholderPasswords, false)...)
+	}
+	if !config.PlaceholderPasswordPattern.IsNull() {
+		pattern, err := regexp.Compile(config.PlaceholderPasswordPattern.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("placeholder_password_pattern"), "Invalid Placeholder Password Pattern", err.Error())
+		}
+		data.PlaceholderPasswordPattern = pattern
+	}
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.DataSourceData = data
+	resp.ResourceData = data
+	resp.EphemeralResourceData = data
 }
 
 func (p *pbkdf2Provider) DataSources(_ context.Context) []func() datasource.DataSource {
-	return []func() datasource.DataSource{}
+	return []func() datasource.DataSource{
+		NewCapabilitiesDataSource,
+		NewCompatibilityDataSource,
+		NewCostEstimateDataSource,
+		NewFingerprintDataSource,
+		NewHmacDataSource,
+		NewKeyDataSource,
+		NewVerifyDataSource,
+	}
 }
 
 func (p *pbkdf2Provider) Resources(_ context.Context) []func() resource.Resource {
 	return []func() resource.Resource{
+		NewBalloonResource,
+		NewEvpBytesToKeyResource,
+		NewFileKeyResource,
+		NewFirebaseScryptResource,
+		NewKeepassKeyResource,
 		NewKeyResource,
+		NewLuksKeyResource,
+		NewPbes1Resource,
+		NewSrpVerifierResource,
+		NewVeracryptKeyResource,
+		NewWeb3KeystoreResource,
+		NewYescryptResource,
+	}
+}
+
+func (p *pbkdf2Provider) EphemeralResources(_ context.Context) []func() ephemeral.EphemeralResource {
+	return []func() ephemeral.EphemeralResource{
+		NewKeyEphemeralResource,
+		NewSaltEphemeralResource,
+	}
+}
+
+func (p *pbkdf2Provider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewBip39SeedFunction,
+		NewHkdfExpandFunction,
+		NewKerberosStringToKeyFunction,
+		NewKmacDeriveFunction,
+		NewNeedsRehashFunction,
+		NewSha512CryptFunction,
+		NewVerifyScramFunction,
 	}
 }
 
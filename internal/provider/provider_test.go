@@ -2,6 +2,7 @@ package provider
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
@@ -12,3 +13,25 @@ var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServe
 }
 
 func testAccPreCheck(t *testing.T) {}
+
+func TestAcquireDerivation(t *testing.T) {
+	data := &pbkdf2ProviderData{Derivations: make(chan struct{}, 1)}
+	release := data.acquireDerivation()
+
+	acquired := make(chan struct{})
+	go func() {
+		defer data.acquireDerivation()()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("a second derivation started while the only slot was taken")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	<-acquired
+
+	// Without a limit nothing blocks.
+	var unlimited *pbkdf2ProviderData
+	unlimited.acquireDerivation()()
+}
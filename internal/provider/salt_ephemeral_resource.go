@@ -0,0 +1,79 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral"
+	"github.com/hashicorp/terraform-plugin-framework/ephemeral/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ ephemeral.EphemeralResource = &SaltEphemeralResource{}
+)
+
+func NewSaltEphemeralResource() ephemeral.EphemeralResource {
+	return &SaltEphemeralResource{}
+}
+
+type SaltEphemeralResource struct{}
+
+type SaltEphemeralResourceData struct {
+	Length types.Int64  `tfsdk:"length"`
+	Hex    types.String `tfsdk:"hex"`
+	Base64 types.String `tfsdk:"base64"`
+}
+
+func (r *SaltEphemeralResource) Metadata(_ context.Context, req ephemeral.MetadataRequest, resp *ephemeral.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_salt"
+}
+
+func (r *SaltEphemeralResource) Schema(_ context.Context, _ ephemeral.SchemaRequest, resp *ephemeral.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Random salt generated whenever it is opened and never written to state or plan, for pipelines that hand the salt " +
+			"together with a hash straight to an external system of record. Every run produces a different salt, so whatever consumes it must store it.",
+
+		Attributes: map[string]schema.Attribute{
+			"length": schema.Int64Attribute{
+				MarkdownDescription: "The length of the salt in bytes. Defaults to `16`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.Between(1, 1024),
+				},
+			},
+			"hex": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded salt.",
+				Computed:            true,
+			},
+			"base64": schema.StringAttribute{
+				MarkdownDescription: "The standard base64 encoded salt.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+func (r *SaltEphemeralResource) Open(ctx context.Context, req ephemeral.OpenRequest, resp *ephemeral.OpenResponse) {
+	var data SaltEphemeralResourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if data.Length.IsNull() {
+		data.Length = types.Int64Value(16)
+	}
+	salt, err := newSalt(data.Length.ValueInt64())
+	if err != nil {
+		resp.Diagnostics.AddError("Salt Error", err.Error())
+		return
+	}
+	data.Hex = types.StringValue(hex.EncodeToString(salt))
+	data.Base64 = types.StringValue(base64.StdEncoding.EncodeToString(salt))
+
+	resp.Diagnostics.Append(resp.Result.Set(ctx, &data)...)
+}
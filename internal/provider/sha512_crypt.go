@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	sha512CryptDefaultRounds = 5000
+	sha512CryptMinRounds     = 1000
+	sha512CryptMaxRounds     = 999999999
+	sha512CryptMaxSalt       = 16
+	cryptAlphabet            = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// sha512CryptPermutation is the byte order in which the final digest is encoded, three bytes at a time.
+var sha512CryptPermutation = [][3]int{
+	{0, 21, 42}, {22, 43, 1}, {44, 2, 23}, {3, 24, 45}, {25, 46, 4}, {47, 5, 26}, {6, 27, 48},
+	{28, 49, 7}, {50, 8, 29}, {9, 30, 51}, {31, 52, 10}, {53, 11, 32}, {12, 33, 54}, {34, 55, 13},
+	{56, 14, 35}, {15, 36, 57}, {37, 58, 16}, {59, 17, 38}, {18, 39, 60}, {40, 61, 19}, {62, 20, 41},
+}
+
+// repeatBytes cycles data until it is length bytes long.
+func repeatBytes(data []byte, length int) []byte {
+	out := make([]byte, 0, length)
+	for len(out) < length {
+		out = append(out, data[:min(len(data), length-len(out))]...)
+	}
+	return out
+}
+
+// sha512Crypt implements the `$6$` scheme from Ulrich Drepper's "Unix crypt using SHA-256 and SHA-512".
+// The salt may carry a `rounds=<n>$` prefix, which is then kept in the output.
+func sha512Crypt(password, salt string) (string, error) {
+	rounds := sha512CryptDefaultRounds
+	customRounds := false
+	if rest, ok := strings.CutPrefix(salt, "rounds="); ok {
+		value, remainder, found := strings.Cut(rest, "$")
+		if !found {
+			return "", fmt.Errorf("expected rounds=<n>$<salt>")
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("rounds is not a number")
+		}
+		rounds = min(max(n, sha512CryptMinRounds), sha512CryptMaxRounds)
+		customRounds = true
+		salt = remainder
+	}
+	if len(salt) > sha512CryptMaxSalt {
+		salt = salt[:sha512CryptMaxSalt]
+	}
+	if strings.ContainsAny(salt, "$:\n") {
+		return "", fmt.Errorf("salt must not contain '$', ':' or newlines")
+	}
+
+	pw := []byte(password)
+	s := []byte(salt)
+
+	b := sha512.New()
+	b.Write(pw)
+	b.Write(s)
+	b.Write(pw)
+	digestB := b.Sum(nil)
+
+	a := sha512.New()
+	a.Write(pw)
+	a.Write(s)
+	a.Write(repeatBytes(digestB, len(pw)))
+	for n := len(pw); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			a.Write(digestB)
+		} else {
+			a.Write(pw)
+		}
+	}
+	digestA := a.Sum(nil)
+
+	dp := sha512.New()
+	for range pw {
+		dp.Write(pw)
+	}
+	p := repeatBytes(dp.Sum(nil), len(pw))
+
+	ds := sha512.New()
+	for i := 0; i < 16+int(digestA[0]); i++ {
+		ds.Write(s)
+	}
+	sBytes := repeatBytes(ds.Sum(nil), len(s))
+
+	c := digestA
+	for i := 0; i < rounds; i++ {
+		h := sha512.New()
+		if i&1 != 0 {
+			h.Write(p)
+		} else {
+			h.Write(c)
+		}
+		if i%3 != 0 {
+			h.Write(sBytes)
+		}
+		if i%7 != 0 {
+			h.Write(p)
+		}
+		if i&1 != 0 {
+			h.Write(c)
+		} else {
+			h.Write(p)
+		}
+		c = h.Sum(nil)
+	}
+
+	var out strings.Builder
+	out.WriteString("$6$")
+	if customRounds {
+		out.WriteString("rounds=" + strconv.Itoa(rounds) + "$")
+	}
+	out.WriteString(salt)
+	out.WriteString("$")
+	for _, t := range sha512CryptPermutation {
+		cryptEncode(&out, uint(c[t[0]])<<16|uint(c[t[1]])<<8|uint(c[t[2]]), 4)
+	}
+	cryptEncode(&out, uint(c[63]), 2)
+	return out.String(), nil
+}
+
+// cryptEncode writes the n low order 6-bit groups of w in the crypt base64 alphabet.
+func cryptEncode(out *strings.Builder, w uint, n int) {
+	for ; n > 0; n-- {
+		out.WriteByte(cryptAlphabet[w&0x3f])
+		w >>= 6
+	}
+}
@@ -0,0 +1,57 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+var (
+	_ function.Function = &Sha512CryptFunction{}
+)
+
+func NewSha512CryptFunction() function.Function {
+	return &Sha512CryptFunction{}
+}
+
+type Sha512CryptFunction struct{}
+
+func (f *Sha512CryptFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "sha512_crypt"
+}
+
+func (f *Sha512CryptFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Hashes a password with sha512-crypt.",
+		MarkdownDescription: "Produces a `$6$` sha512-crypt hash as used in `/etc/shadow` and cloud-init `passwd` entries. The salt is truncated to 16 characters and may be prefixed with `rounds=<n>$` to override the default of 5000 rounds.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "password",
+				MarkdownDescription: "The password to hash.",
+			},
+			function.StringParameter{
+				Name:                "salt",
+				MarkdownDescription: "The salt, optionally prefixed with `rounds=<n>$`.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *Sha512CryptFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var password, salt string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &password, &salt))
+	if resp.Error != nil {
+		return
+	}
+
+	hash, err := sha512Crypt(password, salt)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Invalid salt: "+err.Error())
+		return
+	}
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, hash))
+}
@@ -0,0 +1,45 @@
+package provider
+
+import (
+	"testing"
+)
+
+func TestSha512Crypt(t *testing.T) {
+	// Test vectors from "Unix crypt using SHA-256 and SHA-512".
+	cases := []struct {
+		salt     string
+		password string
+		expected string
+	}{
+		{
+			salt:     "saltstring",
+			password: "Hello world!",
+			expected: "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+		},
+		{
+			salt:     "rounds=10000$saltstringsaltstring",
+			password: "Hello world!",
+			expected: "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.",
+		},
+		{
+			salt:     "rounds=5000$toolongsaltstring",
+			password: "This is just a test",
+			expected: "$6$rounds=5000$toolongsaltstrin$lQ8jolhgVRVhY4b5pZKaysCLi0QBxGoNeKQzQ3glMhwllF7oGDZxUhx1yxdYcz/e1JSbq3y6JMxxl8audkUEm0",
+		},
+		{
+			salt:     "rounds=10$roundstoolow",
+			password: "the minimum number is still observed",
+			expected: "$6$rounds=1000$roundstoolow$kUMsbe306n21p9R.FRkW3IGn.S9NPN0x50YhH1xhLsPuWGsUSklZt58jaTfF4ZEQpyUNGc0dqbpBYYBaHHrsX.",
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := sha512Crypt(c.password, c.salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != c.expected {
+			t.Errorf("sha512Crypt(%q, %q) = %q, want %q", c.password, c.salt, actual, c.expected)
+		}
+	}
+}
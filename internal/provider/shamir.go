@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// gfMul multiplies in GF(2^8) with the AES polynomial, without branching on the operands.
+func gfMul(a, b byte) byte {
+	var p byte
+	for range 8 {
+		p ^= -(b & 1) & a
+		a = a<<1 ^ -(a>>7)&0x1b
+		b >>= 1
+	}
+	return p
+}
+
+// gfInv inverts a non-zero element as a^254.
+func gfInv(a byte) byte {
+	result := byte(1)
+	for range 254 {
+		result = gfMul(result, a)
+	}
+	return result
+}
+
+// shamirSplit splits secret into n shares of which any threshold recover it. Each share
+// holds the polynomial values for every secret byte followed by its x coordinate, the
+// layout of HashiCorp Vault's shamir package.
+func shamirSplit(secret []byte, n, threshold int) ([][]byte, error) {
+	if threshold < 2 || threshold > n || n > 255 {
+		return nil, fmt.Errorf("threshold must be between 2 and the number of shares, at most 255")
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, len(secret)+1)
+		shares[i][len(secret)] = byte(i + 1)
+	}
+	coefficients := make([]byte, threshold-1)
+	for j, b := range secret {
+		if _, err := rand.Read(coefficients); err != nil {
+			return nil, err
+		}
+		for i := range shares {
+			// Horner's scheme for b + c1*x + ... + c(t-1)*x^(t-1).
+			x, y := byte(i+1), byte(0)
+			for k := len(coefficients) - 1; k >= 0; k-- {
+				y = gfMul(y^coefficients[k], x)
+			}
+			shares[i][j] = y ^ b
+		}
+	}
+	return shares, nil
+}
+
+// shamirCombine recovers the secret from shares made by shamirSplit by Lagrange interpolation at 0.
+func shamirCombine(shares [][]byte) ([]byte, error) {
+	if len(shares) < 2 {
+		return nil, fmt.Errorf("at least 2 shares are required")
+	}
+	length := len(shares[0]) - 1
+	for _, share := range shares {
+		if len(share) != length+1 {
+			return nil, fmt.Errorf("shares differ in length")
+		}
+	}
+
+	secret := make([]byte, length)
+	for i, share := range shares {
+		xi := share[length]
+		// The Lagrange basis polynomial of share i at 0: product of xj / (xj - xi).
+		basis := byte(1)
+		for j, other := range shares {
+			if i == j {
+				continue
+			}
+			xj := other[length]
+			if xi == xj {
+				return nil, fmt.Errorf("duplicate share")
+			}
+			basis = gfMul(basis, gfMul(xj, gfInv(xj^xi)))
+		}
+		for k := range secret {
+			secret[k] ^= gfMul(share[k], basis)
+		}
+	}
+	return secret, nil
+}
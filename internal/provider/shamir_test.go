@@ -0,0 +1,52 @@
+package provider
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGFMul(t *testing.T) {
+	// FIPS 197 section 4.2.
+	if actual := gfMul(0x57, 0x83); actual != 0xc1 {
+		t.Errorf("got %#x, want 0xc1", actual)
+	}
+	for a := 1; a < 256; a++ {
+		if gfMul(byte(a), gfInv(byte(a))) != 1 {
+			t.Fatalf("%#x has no inverse", a)
+		}
+	}
+}
+
+func TestShamir(t *testing.T) {
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	shares, err := shamirSplit(secret, 5, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		var picked [][]byte
+		for _, i := range subset {
+			picked = append(picked, shares[i])
+		}
+		recovered, err := shamirCombine(picked)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(recovered, secret) {
+			t.Errorf("shares %v recovered %x", subset, recovered)
+		}
+	}
+
+	recovered, err := shamirCombine(shares[:2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(recovered, secret) {
+		t.Error("2 of 3 required shares recovered the secret")
+	}
+
+	if _, err := shamirSplit(secret, 3, 4); err == nil {
+		t.Error("expected an error for a threshold above the number of shares")
+	}
+}
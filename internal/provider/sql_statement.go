@@ -0,0 +1,27 @@
+package provider
+
+import "strings"
+
+// sqlDialects render a statement setting the stored password hash of a role.
+var sqlDialects = map[string]func(role, hash string) string{
+	"postgresql": func(role, hash string) string {
+		return "ALTER ROLE " + quoteSQLIdentifier(role) + " PASSWORD " + quoteSQLLiteral(hash)
+	},
+	"cockroachdb": func(role, hash string) string {
+		return "ALTER USER " + quoteSQLIdentifier(role) + " WITH PASSWORD " + quoteSQLLiteral(hash)
+	},
+}
+
+func sqlDialectNames() []string {
+	return []string{"postgresql", "cockroachdb"}
+}
+
+// quoteSQLIdentifier quotes a role name, doubling embedded double quotes.
+func quoteSQLIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// quoteSQLLiteral quotes a standard conforming string literal, doubling embedded single quotes.
+func quoteSQLLiteral(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
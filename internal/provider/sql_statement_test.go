@@ -0,0 +1,22 @@
+package provider
+
+import "testing"
+
+func TestSQLDialects(t *testing.T) {
+	cases := []struct {
+		dialect  string
+		role     string
+		hash     string
+		expected string
+	}{
+		{"postgresql", "app", "SCRAM-SHA-256$4096:c2FsdA==$a:b", `ALTER ROLE "app" PASSWORD 'SCRAM-SHA-256$4096:c2FsdA==$a:b'`},
+		{"postgresql", `we"ird`, "it's", `ALTER ROLE "we""ird" PASSWORD 'it''s'`},
+		{"cockroachdb", "App", "SCRAM-SHA-256$4096:c2FsdA==$a:b", `ALTER USER "App" WITH PASSWORD 'SCRAM-SHA-256$4096:c2FsdA==$a:b'`},
+	}
+
+	for _, c := range cases {
+		if actual := sqlDialects[c.dialect](c.role, c.hash); actual != c.expected {
+			t.Errorf("%s: got %q, want %q", c.dialect, actual, c.expected)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package provider
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestSRPVerifier(t *testing.T) {
+	// RFC 5054 appendix B.
+	salt, _ := hex.DecodeString("BEB25379D1A8581EB5A727673A2441EE")
+	expected := "7E273DE8696FFC4F4E337D05B4B375BEB0DDE1569E8FA00A9886D8129BADA1F1822223CA1A605B530E379BA4729FDC59" +
+		"F105B4787E5186F5C671085A1447B52A48CF1970B4FB6F8400BBF4CEBFBB168152E08AB5EA53D15C1AFF87B2B9DA6E04" +
+		"E058AD51CC72BFC9033B564E26480D78E955A5E29E7AB245DB2BE315E2099AFB"
+
+	verifier := srpVerifier(srpGroups["1024"], sha1.New, "alice", "password123", salt)
+	if actual := strings.ToUpper(hex.EncodeToString(verifier)); actual != expected {
+		t.Errorf("got %s, want %s", actual, expected)
+	}
+}
+
+func TestSRPGroups(t *testing.T) {
+	for bits, group := range srpGroups {
+		n, ok := new(big.Int).SetString(group.N, 16)
+		if !ok || bits != big.NewInt(int64(n.BitLen())).String() || !n.ProbablyPrime(4) {
+			t.Errorf("group %s has an invalid modulus", bits)
+		}
+	}
+}
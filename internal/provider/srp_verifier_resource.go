@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"context"
+	"encoding/hex"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource = &SrpVerifierResource{}
+)
+
+func NewSrpVerifierResource() resource.Resource {
+	return &SrpVerifierResource{}
+}
+
+type SrpVerifierResource struct{}
+
+func (r *SrpVerifierResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_srp_verifier"
+}
+
+func (r *SrpVerifierResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "SRP-6a salt and verifier as defined by RFC 5054, `v = g^x mod N` with `x = H(salt | H(username \":\" password))`, " +
+			"for services that authenticate with the Secure Remote Password protocol instead of stored password hashes.",
+
+		Attributes: map[string]schema.Attribute{
+			"username": schema.StringAttribute{
+				MarkdownDescription: "The username, which is part of the verifier.",
+				Required:            true,
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to compute the verifier of.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"group": schema.StringAttribute{
+				MarkdownDescription: "Size in bits of the RFC 5054 group: `1024`, `1536`, `2048`, `3072` or `4096`. Defaults to `2048`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("2048"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("1024", "1536", "2048", "3072", "4096"),
+				},
+			},
+			"hash_algorithm": schema.StringAttribute{
+				MarkdownDescription: "The hash function H: `sha1`, `sha256` or `sha512`. Defaults to `sha1`, as specified by RFC 5054.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("sha1"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("sha1", "sha256", "sha512"),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded salt. Generated from 16 random bytes when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^([0-9a-fA-F]{2})+$`), "must be hex encoded bytes"),
+				},
+			},
+			"verifier": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded verifier.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type SrpVerifierResourceData struct {
+	Username      types.String `tfsdk:"username"`
+	Password      types.String `tfsdk:"password"`
+	Group         types.String `tfsdk:"group"`
+	HashAlgorithm types.String `tfsdk:"hash_algorithm"`
+	Salt          types.String `tfsdk:"salt"`
+	Verifier      types.String `tfsdk:"verifier"`
+}
+
+// computeSRPVerifier fills in salt and verifier of data.
+func computeSRPVerifier(data *SrpVerifierResourceData, diags *diag.Diagnostics) {
+	var salt []byte
+	if data.Salt.IsUnknown() || data.Salt.IsNull() {
+		var err error
+		salt, err = newSalt(16)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(hex.EncodeToString(salt))
+	} else {
+		var err error
+		salt, err = hex.DecodeString(data.Salt.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt"), "Invalid Salt", "The salt is not hex encoded: "+err.Error()+".")
+			return
+		}
+	}
+
+	verifier := srpVerifier(srpGroups[data.Group.ValueString()], srpHashes[data.HashAlgorithm.ValueString()],
+		data.Username.ValueString(), data.Password.ValueString(), salt)
+	data.Verifier = types.StringValue(hex.EncodeToString(verifier))
+}
+
+func (r *SrpVerifierResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan SrpVerifierResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	computeSRPVerifier(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *SrpVerifierResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *SrpVerifierResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state SrpVerifierResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	computeSRPVerifier(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *SrpVerifierResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,46 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccSrpVerifierResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_srp_verifier" "test" {
+  username = "alice"
+  password = "password123"
+  group    = "1024"
+  salt     = "beb25379d1a8581eb5a727673a2441ee"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_srp_verifier.test", "verifier", "7e273de8696ffc4f4e337d05b4b375beb0dde1569e8fa00a9886d8129bada1f1"+
+						"822223ca1a605b530e379ba4729fdc59f105b4787e5186f5c671085a1447b52a48cf1970b4fb6f8400bbf4cebfbb1681"+
+						"52e08ab5ea53d15c1aff87b2b9da6e04e058ad51cc72bfc9033b564e26480d78e955a5e29e7ab245db2be315e2099afb"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_srp_verifier" "test" {
+  username       = "alice"
+  password       = "password123"
+  hash_algorithm = "sha256"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The salt is kept when it is no longer configured.
+					resource.TestCheckResourceAttr("pbkdf2_srp_verifier.test", "salt", "beb25379d1a8581eb5a727673a2441ee"),
+					resource.TestMatchResourceAttr("pbkdf2_srp_verifier.test", "verifier", regexp.MustCompile(`^[0-9a-f]+$`)),
+				),
+			},
+		},
+	})
+}
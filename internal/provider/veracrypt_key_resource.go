@@ -0,0 +1,197 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"hash"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/blake2s"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	_ resource.Resource = &VeracryptKeyResource{}
+)
+
+// veracryptHashes are the VeraCrypt PRF hashes available here. Whirlpool and Streebog have no Go implementation
+// in golang.org/x/crypto.
+var veracryptHashes = map[string]func() hash.Hash{
+	"sha512": sha512.New,
+	"sha256": sha256.New,
+	"blake2s-256": func() hash.Hash {
+		h, _ := blake2s.New256(nil)
+		return h
+	},
+}
+
+// veracryptIterations is the iteration count VeraCrypt uses for a PIM: 15000 + PIM * 1000, except for system
+// encryption with hashes other than SHA-512, which uses PIM * 2048. A PIM of 0 selects the default.
+func veracryptIterations(hashName string, pim int64, system bool) int64 {
+	if system && hashName != "sha512" {
+		if pim == 0 {
+			return 200000
+		}
+		return pim * 2048
+	}
+	if pim == 0 {
+		return 500000
+	}
+	return 15000 + pim*1000
+}
+
+func NewVeracryptKeyResource() resource.Resource {
+	return &VeracryptKeyResource{}
+}
+
+type VeracryptKeyResource struct{}
+
+func (r *VeracryptKeyResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_veracrypt_key"
+}
+
+func (r *VeracryptKeyResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "VeraCrypt header key, derived with PBKDF2 from a password and the salt at the start of the volume header, " +
+			"with the iterations VeraCrypt uses for a PIM, for automation that prepares encrypted container headers. " +
+			"Whirlpool and Streebog are not supported.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The volume password.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"hash": schema.StringAttribute{
+				MarkdownDescription: "The PRF hash: `sha512`, `sha256` or `blake2s-256`. Defaults to `sha512`, the VeraCrypt default.",
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("sha512"),
+				Validators: []validator.String{
+					stringvalidator.OneOf("sha512", "sha256", "blake2s-256"),
+				},
+			},
+			"pim": schema.Int64Attribute{
+				MarkdownDescription: "The Personal Iterations Multiplier. `0` selects the VeraCrypt default iterations. Defaults to `0`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(0),
+				Validators: []validator.Int64{
+					int64validator.Between(0, 2147468),
+				},
+			},
+			"system_encryption": schema.BoolAttribute{
+				MarkdownDescription: "Whether the header is for system encryption, which counts the iterations of a PIM differently for hashes other than SHA-512. Defaults to `false`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+			},
+			"key_length": schema.Int64Attribute{
+				MarkdownDescription: "Length of the header key in bytes: `64` for a single XTS cipher, `128` and `192` for cascades of two and three. Defaults to `64`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(64),
+				Validators: []validator.Int64{
+					int64validator.OneOf(64, 128, 192),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded 64 byte header salt. Generated randomly when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "The number of iterations, from `hash`, `pim` and `system_encryption`.",
+				Computed:            true,
+			},
+			"key": schema.StringAttribute{
+				MarkdownDescription: "The base64 encoded header key.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type VeracryptKeyResourceData struct {
+	Password         types.String `tfsdk:"password"`
+	Hash             types.String `tfsdk:"hash"`
+	Pim              types.Int64  `tfsdk:"pim"`
+	SystemEncryption types.Bool   `tfsdk:"system_encryption"`
+	KeyLength        types.Int64  `tfsdk:"key_length"`
+	Salt             types.String `tfsdk:"salt"`
+	Iterations       types.Int64  `tfsdk:"iterations"`
+	Key              types.String `tfsdk:"key"`
+}
+
+// deriveVeracryptKey fills in salt, iterations and key of data.
+func deriveVeracryptKey(data *VeracryptKeyResourceData, diags *diag.Diagnostics) {
+	var salt []byte
+	if data.Salt.IsUnknown() || data.Salt.IsNull() {
+		var err error
+		salt, err = newSalt(64)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		data.Salt = types.StringValue(base64.StdEncoding.EncodeToString(salt))
+	} else if salt = decodeFixed(data.Salt, "salt", 64, diags); diags.HasError() {
+		return
+	}
+
+	iterations := veracryptIterations(data.Hash.ValueString(), data.Pim.ValueInt64(), data.SystemEncryption.ValueBool())
+	data.Iterations = types.Int64Value(iterations)
+	key := pbkdf2.Key([]byte(data.Password.ValueString()), salt, int(iterations), int(data.KeyLength.ValueInt64()),
+		veracryptHashes[data.Hash.ValueString()])
+	data.Key = types.StringValue(base64.StdEncoding.EncodeToString(key))
+}
+
+func (r *VeracryptKeyResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan VeracryptKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveVeracryptKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *VeracryptKeyResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *VeracryptKeyResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state VeracryptKeyResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	deriveVeracryptKey(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *VeracryptKeyResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
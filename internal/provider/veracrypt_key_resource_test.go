@@ -0,0 +1,69 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestVeracryptIterations(t *testing.T) {
+	cases := []struct {
+		hash     string
+		pim      int64
+		system   bool
+		expected int64
+	}{
+		{"sha512", 0, false, 500000},
+		{"sha512", 0, true, 500000},
+		{"sha256", 0, true, 200000},
+		{"sha256", 0, false, 500000},
+		{"sha512", 485, false, 500000},
+		{"sha256", 98, true, 200704},
+		{"blake2s-256", 1, false, 16000},
+	}
+	for _, c := range cases {
+		if actual := veracryptIterations(c.hash, c.pim, c.system); actual != c.expected {
+			t.Errorf("%s pim %d system %t: expected %d, got %d", c.hash, c.pim, c.system, c.expected, actual)
+		}
+	}
+}
+
+func TestAccVeracryptKeyResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_veracrypt_key" "sha512" {
+  password = "password"
+  pim      = 1
+  salt     = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8gISIjJCUmJygpKissLS4vMDEyMzQ1Njc4OTo7PD0+Pw=="
+}
+
+resource "pbkdf2_veracrypt_key" "system" {
+  password          = "password"
+  hash              = "sha256"
+  pim               = 1
+  system_encryption = true
+  salt              = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8gISIjJCUmJygpKissLS4vMDEyMzQ1Njc4OTo7PD0+Pw=="
+}
+
+resource "pbkdf2_veracrypt_key" "blake2s" {
+  password = "password"
+  hash     = "blake2s-256"
+  pim      = 1
+  salt     = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8gISIjJCUmJygpKissLS4vMDEyMzQ1Njc4OTo7PD0+Pw=="
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_veracrypt_key.sha512", "iterations", "16000"),
+					resource.TestCheckResourceAttr("pbkdf2_veracrypt_key.sha512", "key", "GAvHcHC9GWXh2PXJQ8rUOq/XQ9ojwfB4dyDk0gVJY3DjlHXNZ1p0aQ4mcc10sn3Ph2GLDVpCbsEVMZA6ZuieUQ=="),
+					resource.TestCheckResourceAttr("pbkdf2_veracrypt_key.system", "iterations", "2048"),
+					resource.TestCheckResourceAttr("pbkdf2_veracrypt_key.system", "key", "vKSwCHKGxwoc++1o/xkiPgw8nNUdGtgPO1+yRQPAbZDp1C6NwK8oIXEV7aQUFxCKnkBqawHncQPV5hSI0SJRLw=="),
+					resource.TestCheckResourceAttr("pbkdf2_veracrypt_key.blake2s", "key", "mFloxHxM83R9DZUJxqZkI1u/du2tMOAnTzkPu8BLT8kdc+AqKTu73fPQVc8dGGKVGwdrGxPEwbcQ7MX4HgR/GA=="),
+				),
+			},
+		},
+	})
+}
@@ -0,0 +1,205 @@
+package provider
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ datasource.DataSource              = &VerifyDataSource{}
+	_ datasource.DataSourceWithConfigure = &VerifyDataSource{}
+)
+
+func NewVerifyDataSource() datasource.DataSource {
+	return &VerifyDataSource{}
+}
+
+type VerifyDataSource struct {
+	provider *pbkdf2ProviderData
+}
+
+func (d *VerifyDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_verify"
+}
+
+func (d *VerifyDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	if req.ProviderData == nil {
+		return
+	}
+
+	data, ok := req.ProviderData.(*pbkdf2ProviderData)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Unexpected Data Source Configure Type",
+			fmt.Sprintf("Expected *pbkdf2ProviderData, got: %T. Please report this issue to the provider developers.", req.ProviderData),
+		)
+		return
+	}
+
+	d.provider = data
+}
+
+func (d *VerifyDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Verifies a password against one or more stored PBKDF2 hashes. The provider's `pepper` and `derivation_context` apply as for `pbkdf2_key`, " +
+			"so hashes it produced verify against their password. PHC, passlib and Django hashes are checked without `derivation_context`, as `pbkdf2_key` never renders them with one.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The candidate password.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"hashes": schema.ListAttribute{
+				MarkdownDescription: "Stored hashes to check, in the default `<b64 salt>:<b64 key>` format of `pbkdf2_key`, or in a layout that records its own parameters: " +
+					"PHC and passlib (`$pbkdf2-sha256$29000$<salt>$<key>`) or Django (`pbkdf2_sha256$600000$<salt>$<key>`).",
+				ElementType: types.StringType,
+				Required:    true,
+				Sensitive:   true,
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of iterations the `<b64 salt>:<b64 key>` hashes were derived with. Defaults to the provider's `default_iterations`.",
+				Optional:            true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"prf": schema.StringAttribute{
+				MarkdownDescription: "The pseudorandom function the `<b64 salt>:<b64 key>` hashes were derived with: " + markdownList(pbkdf2kit.PRFNames()) + ". Defaults to the provider's `default_prf`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(pbkdf2kit.PRFNames()...),
+					stringvalidator.ConflictsWith(path.MatchRoot("hash_algorithm")),
+				},
+			},
+			"hash_algorithm": schema.StringAttribute{
+				MarkdownDescription: "The hash function the `<b64 salt>:<b64 key>` hashes were derived with, as the bare hash name of `prf`: " + markdownList(prfAliases()) + ".",
+				DeprecationMessage:  "Use prf instead, e.g. hmac-sha256 instead of sha256.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(prfAliases()...),
+				},
+			},
+			"pepper": schema.StringAttribute{
+				MarkdownDescription: "Pepper the hashes were derived with, overriding the provider `pepper`, as for a `pbkdf2_key` with its own `pepper`.",
+				Optional:            true,
+				Sensitive:           true,
+			},
+			"pepper_mode": schema.StringAttribute{
+				MarkdownDescription: "How the pepper was applied to the passwords, as the `pepper_mode` of `pbkdf2_key`: `hmac` or `concat`. Defaults to `hmac`.",
+				Optional:            true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("hmac", "concat"),
+				},
+			},
+			"match_index": schema.Int64Attribute{
+				MarkdownDescription: "Index of the first hash in `hashes` matching the password, or `-1` if none match.",
+				Computed:            true,
+			},
+		},
+	}
+}
+
+type VerifyDataSourceData struct {
+	Password      types.String `tfsdk:"password"`
+	Hashes        types.List   `tfsdk:"hashes"`
+	Iterations    types.Int64  `tfsdk:"iterations"`
+	Prf           types.String `tfsdk:"prf"`
+	HashAlgorithm types.String `tfsdk:"hash_algorithm"`
+	Pepper        types.String `tfsdk:"pepper"`
+	PepperMode    types.String `tfsdk:"pepper_mode"`
+	MatchIndex    types.Int64  `tfsdk:"match_index"`
+}
+
+func (d *VerifyDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var data VerifyDataSourceData
+	resp.Diagnostics.Append(req.Config.Get(ctx, &data)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var hashes []string
+	resp.Diagnostics.Append(data.Hashes.ElementsAs(ctx, &hashes, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	params := pbkdf2kit.Params{
+		PRF:        d.provider.defaultPRF(),
+		Iterations: int(d.provider.defaultIterations()),
+		Pepper:     d.provider.pepper(),
+		PepperMode: "hmac",
+		Context:    d.provider.derivationContext(),
+	}
+	if !data.Iterations.IsNull() {
+		params.Iterations = int(data.Iterations.ValueInt64())
+	}
+	if !data.Prf.IsNull() {
+		params.PRF = data.Prf.ValueString()
+	} else if !data.HashAlgorithm.IsNull() {
+		params.PRF = lookupPRF(data.HashAlgorithm.ValueString()).Name
+	}
+	if !data.Pepper.IsNull() {
+		params.Pepper = data.Pepper.ValueString()
+	}
+	if !data.PepperMode.IsNull() {
+		params.PepperMode = data.PepperMode.ValueString()
+	}
+
+	matchIndex := -1
+	for i, hash := range hashes {
+		salt, key, hashParams, err := verifyParams(hash, params)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("hashes").AtListIndex(i), "Invalid Hash", err.Error())
+			return
+		}
+		dk, err := pbkdf2kit.Derive(hashParams, data.Password.ValueString(), salt)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("password"), "Derivation Error", err.Error())
+			return
+		}
+		if subtle.ConstantTimeCompare(dk, key) == 1 {
+			matchIndex = i
+			break
+		}
+	}
+
+	data.MatchIndex = types.Int64Value(int64(matchIndex))
+	resp.Diagnostics.Append(resp.State.Set(ctx, &data)...)
+}
+
+// verifyParams splits hash into its salt and key and the parameters to derive the key with. The default
+// `<b64 salt>:<b64 key>` layout doesn't record its parameters, so those of the data source apply, while
+// PHC, passlib and Django hashes carry their own hash and iteration count and are checked without the provider's
+// derivation_context. Errors never quote the hash.
+func verifyParams(hash string, params pbkdf2kit.Params) ([]byte, []byte, pbkdf2kit.Params, error) {
+	if salt, key, err := pbkdf2kit.ParseSaltKey(hash); err == nil {
+		params.KeyLength = len(key)
+		return salt, key, params, nil
+	}
+	parsed, err := pbkdf2kit.ParseHash(hash)
+	if err != nil {
+		return nil, nil, params, err
+	}
+	// The hashes of ParseHash are named by the bare hash, the alias of their HMAC PRF.
+	p, ok := pbkdf2kit.LookupPRF(parsed.HashAlgorithm)
+	if !ok || p.Alias != parsed.HashAlgorithm {
+		return nil, nil, params, fmt.Errorf("unsupported hash %q", parsed.HashAlgorithm)
+	}
+	params.PRF = p.Name
+	params.Iterations = parsed.Iterations
+	// The hash was made by its own system, which knows nothing of the provider's derivation_context.
+	params.Context = ""
+	params.KeyLength = len(parsed.Key)
+	return parsed.Salt, parsed.Key, params, nil
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccVerifyDataSource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccVerifyDataSourceConfig("password"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "1"),
+				),
+			},
+			{
+				Config: testAccVerifyDataSourceConfig("wrong"),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "-1"),
+				),
+			},
+			{
+				Config: `
+data "pbkdf2_verify" "test" {
+  password = "password"
+  prf      = "hmac-sha1"
+  hashes = [
+    "$pbkdf2-sha256$1000$c2FsdA$AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+    "pbkdf2_sha256$1000$salt$YywoEuRtRgQQK6dhjp1tfS+BKPYma0oDJk0qBGC33LM=",
+  ]
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.pbkdf2_verify.test", "match_index", "1"),
+				),
+			},
+			{
+				// A hash with an empty key would match every password.
+				Config: `
+data "pbkdf2_verify" "test" {
+  password = "password"
+  hashes   = ["c2FsdA==:"]
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid Hash`),
+			},
+		},
+	})
+}
+
+func testAccVerifyDataSourceConfig(password string) string {
+	return fmt.Sprintf(`
+data "pbkdf2_verify" "test" {
+  password   = %[1]q
+  iterations = 1
+  hashes = [
+    "c2FsdA==:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+    "c2FsdA==:Eg+2z/z4syxD5yJSVsT4N6hlSMkszDVICAWYfLcL4Xs=",
+  ]
+}
+`, password)
+}
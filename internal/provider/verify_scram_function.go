@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"golang.org/x/crypto/pbkdf2"
+)
+
+var (
+	_ function.Function = &VerifyScramFunction{}
+)
+
+func NewVerifyScramFunction() function.Function {
+	return &VerifyScramFunction{}
+}
+
+type VerifyScramFunction struct{}
+
+func (f *VerifyScramFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "verify_scram"
+}
+
+func (f *VerifyScramFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary: "Checks a password against a SCRAM-SHA-256 verifier.",
+		MarkdownDescription: "Recomputes the StoredKey and ServerKey of `password` with the salt and iteration count of a " +
+			"`SCRAM-SHA-256$<iterations>:<b64 salt>$<b64 StoredKey>:<b64 ServerKey>` verifier, as stored in PostgreSQL's `pg_authid`, " +
+			"and returns `true` when both match.",
+
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "password",
+				MarkdownDescription: "The candidate password.",
+			},
+			function.StringParameter{
+				Name:                "verifier",
+				MarkdownDescription: "The stored verifier.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f *VerifyScramFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var password, verifier string
+
+	resp.Error = function.ConcatFuncErrors(req.Arguments.Get(ctx, &password, &verifier))
+	if resp.Error != nil {
+		return
+	}
+
+	parsed, err := pbkdf2kit.ParseSCRAMVerifier(verifier)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(1, "Invalid verifier: "+err.Error())
+		return
+	}
+
+	storedKey, serverKey := pbkdf2kit.SCRAMKeys(pbkdf2.Key([]byte(password), parsed.Salt, parsed.Iterations, sha256.Size, sha256.New))
+	match := subtle.ConstantTimeCompare(storedKey, parsed.StoredKey)&subtle.ConstantTimeCompare(serverKey, parsed.ServerKey) == 1
+
+	resp.Error = function.ConcatFuncErrors(resp.Result.Set(ctx, match))
+}
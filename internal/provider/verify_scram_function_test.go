@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/tfversion"
+)
+
+func TestAccVerifyScramFunction(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		TerraformVersionChecks: []tfversion.TerraformVersionCheck{
+			tfversion.SkipBelow(tfversion.Version1_8_0),
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: `
+locals {
+  verifier = "SCRAM-SHA-256$4096:c2FsdC1mb3ItcGVuY2lsIQ==$qYIaB/m/tpnqMLDfPtE/qzjPOnnmhgn6gQCzOElTYqs=:hfb2Bd0V9bE3wFaapQhVZPBHXEUYhI3OanY5j3lTQbk="
+}
+
+output "match" {
+  value = provider::pbkdf2::verify_scram("pencil", local.verifier)
+}
+
+output "mismatch" {
+  value = provider::pbkdf2::verify_scram("crayon", local.verifier)
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckOutput("match", "true"),
+					resource.TestCheckOutput("mismatch", "false"),
+				),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::pbkdf2::verify_scram("pencil", "md5c3b1ff8e3e6a4b0a0f8c7d2b1e8f9a0b1")
+}
+`,
+				ExpectError: regexp.MustCompile(`Invalid verifier: unsupported scheme, expected SCRAM-SHA-256`),
+			},
+		},
+	})
+}
@@ -0,0 +1,266 @@
+package provider
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	_ resource.Resource = &Web3KeystoreResource{}
+)
+
+func NewWeb3KeystoreResource() resource.Resource {
+	return &Web3KeystoreResource{}
+}
+
+type Web3KeystoreResource struct{}
+
+func (r *Web3KeystoreResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_web3_keystore"
+}
+
+func (r *Web3KeystoreResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Ethereum keystore, the version 3 Web3 Secret Storage JSON, wrapping a private key with a PBKDF2 derived key, AES-128-CTR and a Keccak-256 MAC, " +
+			"so signer keystores can be generated declaratively. The keystore has no `address`, as deriving it needs secp256k1.",
+
+		Attributes: map[string]schema.Attribute{
+			"private_key": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded 32 byte private key, with or without `0x`.",
+				Required:            true,
+				Sensitive:           true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^(0x)?[0-9a-fA-F]{64}$`), "must be 32 hex encoded bytes"),
+				},
+			},
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The keystore password.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"iterations": schema.Int64Attribute{
+				MarkdownDescription: "Number of PBKDF2 iterations, `c` in the keystore. Defaults to `262144`, as geth uses.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(262144),
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded 32 byte salt. Generated when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-fA-F]{64}$`), "must be 32 hex encoded bytes"),
+				},
+			},
+			"iv": schema.StringAttribute{
+				MarkdownDescription: "The hex encoded 16 byte AES-128-CTR IV. Generated when not set, and kept across updates that keep `private_key`.",
+				Optional:            true,
+				Computed:            true,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[0-9a-fA-F]{32}$`), "must be 16 hex encoded bytes"),
+				},
+			},
+			"keystore_id": schema.StringAttribute{
+				MarkdownDescription: "The UUID of the keystore, `id` in the keystore. Generated when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+			},
+			"keystore_json": schema.StringAttribute{
+				MarkdownDescription: "The keystore JSON.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type Web3KeystoreResourceData struct {
+	PrivateKey   types.String `tfsdk:"private_key"`
+	Password     types.String `tfsdk:"password"`
+	Iterations   types.Int64  `tfsdk:"iterations"`
+	Salt         types.String `tfsdk:"salt"`
+	Iv           types.String `tfsdk:"iv"`
+	KeystoreID   types.String `tfsdk:"keystore_id"`
+	KeystoreJSON types.String `tfsdk:"keystore_json"`
+}
+
+// web3Keystore is the version 3 Web3 Secret Storage format with the PBKDF2 KDF.
+type web3Keystore struct {
+	Crypto struct {
+		Cipher       string `json:"cipher"`
+		CipherParams struct {
+			IV string `json:"iv"`
+		} `json:"cipherparams"`
+		Ciphertext string `json:"ciphertext"`
+		KDF        string `json:"kdf"`
+		KDFParams  struct {
+			C     int64  `json:"c"`
+			DKLen int    `json:"dklen"`
+			PRF   string `json:"prf"`
+			Salt  string `json:"salt"`
+		} `json:"kdfparams"`
+		MAC string `json:"mac"`
+	} `json:"crypto"`
+	ID      string `json:"id"`
+	Version int    `json:"version"`
+}
+
+// web3Encrypt encrypts privateKey as the keystore does, returning the ciphertext and its MAC: the first half
+// of the derived key is the AES-128-CTR key, the second half is hashed with the ciphertext into the MAC.
+func web3Encrypt(privateKey, password, salt, iv []byte, iterations int) ([]byte, []byte, error) {
+	dk := pbkdf2.Key(password, salt, iterations, 32, sha256.New)
+	block, err := aes.NewCipher(dk[:16])
+	if err != nil {
+		return nil, nil, err
+	}
+	ciphertext := make([]byte, len(privateKey))
+	cipher.NewCTR(block, iv).XORKeyStream(ciphertext, privateKey)
+
+	mac := sha3.NewLegacyKeccak256()
+	mac.Write(dk[16:32])
+	mac.Write(ciphertext)
+	return ciphertext, mac.Sum(nil), nil
+}
+
+// newUUID returns a random version 4 UUID.
+func newUUID() (string, error) {
+	b, err := newSalt(16)
+	if err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// generatedHex returns the hex attribute value, or a random one of the given length when it is not known.
+func generatedHex(value types.String, length int64, diags *diag.Diagnostics) types.String {
+	if !value.IsUnknown() && !value.IsNull() {
+		return value
+	}
+	b, err := newSalt(length)
+	if err != nil {
+		diags.AddError("Salt Error", err.Error())
+		return value
+	}
+	return types.StringValue(hex.EncodeToString(b))
+}
+
+// deriveWeb3Keystore fills in salt, iv, keystore_id and keystore_json of data.
+func deriveWeb3Keystore(data *Web3KeystoreResourceData, diags *diag.Diagnostics) {
+	data.Salt = generatedHex(data.Salt, 32, diags)
+	data.Iv = generatedHex(data.Iv, 16, diags)
+	if data.KeystoreID.IsUnknown() || data.KeystoreID.IsNull() {
+		id, err := newUUID()
+		if err != nil {
+			diags.AddError("Keystore ID Error", err.Error())
+			return
+		}
+		data.KeystoreID = types.StringValue(id)
+	}
+	if diags.HasError() {
+		return
+	}
+
+	privateKey, err := hex.DecodeString(strings.TrimPrefix(data.PrivateKey.ValueString(), "0x"))
+	if err != nil {
+		// The private key is secret, so it is not quoted.
+		diags.AddAttributeError(path.Root("private_key"), "Invalid Private Key", "The private key is not valid hex.")
+		return
+	}
+	salt, _ := hex.DecodeString(data.Salt.ValueString())
+	iv, _ := hex.DecodeString(data.Iv.ValueString())
+	ciphertext, mac, err := web3Encrypt(privateKey, []byte(data.Password.ValueString()), salt, iv, int(data.Iterations.ValueInt64()))
+	if err != nil {
+		diags.AddError("Encryption Error", err.Error())
+		return
+	}
+
+	var keystore web3Keystore
+	keystore.Crypto.Cipher = "aes-128-ctr"
+	keystore.Crypto.CipherParams.IV = data.Iv.ValueString()
+	keystore.Crypto.Ciphertext = hex.EncodeToString(ciphertext)
+	keystore.Crypto.KDF = "pbkdf2"
+	keystore.Crypto.KDFParams.C = data.Iterations.ValueInt64()
+	keystore.Crypto.KDFParams.DKLen = 32
+	keystore.Crypto.KDFParams.PRF = "hmac-sha256"
+	keystore.Crypto.KDFParams.Salt = data.Salt.ValueString()
+	keystore.Crypto.MAC = hex.EncodeToString(mac)
+	keystore.ID = data.KeystoreID.ValueString()
+	keystore.Version = 3
+
+	encoded, err := json.Marshal(keystore)
+	if err != nil {
+		diags.AddError("Encoding Error", err.Error())
+		return
+	}
+	data.KeystoreJSON = types.StringValue(string(encoded))
+}
+
+func (r *Web3KeystoreResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan Web3KeystoreResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	deriveWeb3Keystore(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Web3KeystoreResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *Web3KeystoreResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state Web3KeystoreResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	// CTR mode must not reuse an IV for a different private key under the same key.
+	if plan.Iv.IsUnknown() && plan.PrivateKey.Equal(state.PrivateKey) {
+		plan.Iv = state.Iv
+	}
+	if plan.KeystoreID.IsUnknown() {
+		plan.KeystoreID = state.KeystoreID
+	}
+	deriveWeb3Keystore(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *Web3KeystoreResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
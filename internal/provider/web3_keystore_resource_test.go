@@ -0,0 +1,85 @@
+package provider
+
+import (
+	"encoding/hex"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+// The PBKDF2 test vector of the Web3 Secret Storage Definition.
+const (
+	web3TestPrivateKey = "7a28b5ba57c53603b0b07b56bba752f7784bf506fa95edc395f5cf6c7514fe9d"
+	web3TestSalt       = "ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"
+	web3TestIV         = "6087dab2f9fdbbfaddc31a909735c1e6"
+)
+
+func TestWeb3Encrypt(t *testing.T) {
+	privateKey, _ := hex.DecodeString(web3TestPrivateKey)
+	salt, _ := hex.DecodeString(web3TestSalt)
+	iv, _ := hex.DecodeString(web3TestIV)
+	ciphertext, mac, err := web3Encrypt(privateKey, []byte("testpassword"), salt, iv, 262144)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := hex.EncodeToString(ciphertext); actual != "5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46" {
+		t.Errorf("ciphertext: got %s", actual)
+	}
+	if actual := hex.EncodeToString(mac); actual != "517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2" {
+		t.Errorf("mac: got %s", actual)
+	}
+}
+
+func TestNewUUID(t *testing.T) {
+	id, err := newUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(id) {
+		t.Errorf("not a version 4 UUID: %s", id)
+	}
+}
+
+func TestAccWeb3KeystoreResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_web3_keystore" "test" {
+  private_key = "0x` + web3TestPrivateKey + `"
+  password    = "testpassword"
+  salt        = "` + web3TestSalt + `"
+  iv          = "` + web3TestIV + `"
+  keystore_id = "3198bc9c-6672-5ab3-d995-4942343ae5b6"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_web3_keystore.test", "keystore_json",
+						`{"crypto":{"cipher":"aes-128-ctr","cipherparams":{"iv":"6087dab2f9fdbbfaddc31a909735c1e6"},`+
+							`"ciphertext":"5318b4d5bcd28de64ee5559e671353e16f075ecae9f99c7a79a38af5f869aa46","kdf":"pbkdf2",`+
+							`"kdfparams":{"c":262144,"dklen":32,"prf":"hmac-sha256","salt":"ae3cd4e7013836a3df6bd7241b12db061dbe2c6785853cce422d148a624ce0bd"},`+
+							`"mac":"517ead924a9d0dc3124507e3393d175ce3ff7c1e96529c6c555ce9e51205e9b2"},`+
+							`"id":"3198bc9c-6672-5ab3-d995-4942343ae5b6","version":3}`),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_web3_keystore" "test" {
+  private_key = "` + web3TestPrivateKey + `"
+  password    = "testpassword"
+  iterations  = 1000
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// Salt, IV and ID are kept when they are no longer configured.
+					resource.TestCheckResourceAttr("pbkdf2_web3_keystore.test", "salt", web3TestSalt),
+					resource.TestCheckResourceAttr("pbkdf2_web3_keystore.test", "iv", web3TestIV),
+					resource.TestCheckResourceAttr("pbkdf2_web3_keystore.test", "keystore_id", "3198bc9c-6672-5ab3-d995-4942343ae5b6"),
+				),
+			},
+		},
+	})
+}
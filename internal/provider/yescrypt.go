@@ -0,0 +1,348 @@
+package provider
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// yescrypt as specified by the reference implementation, limited to what `$y$` hashes
+// from libxcrypt use: the YESCRYPT_DEFAULTS flavor, p = 1, t = 0 and no ROM.
+
+// pwxform parameters of the YESCRYPT_DEFAULTS flavor.
+const (
+	yescryptFlavor   = 0xb6 // YESCRYPT_RW | ROUNDS_6 | GATHER_4 | SIMPLE_2 | SBOX_12K
+	pwxSimple        = 2
+	pwxGather        = 4
+	pwxRounds        = 6
+	pwxSwidth        = 8
+	pwxWords         = pwxGather * pwxSimple * 2
+	pwxSWords        = 3 * (1 << pwxSwidth) * pwxSimple * 2
+	pwxSMask         = ((1 << pwxSwidth) - 1) * pwxSimple * 8
+	yescryptHashSize = 32
+)
+
+// pwxformCtx holds the S-boxes and write position of one pwxform instance.
+type pwxformCtx struct {
+	s0, s1, s2 []uint32
+	w          int
+}
+
+// salsa20 applies the Salsa20 core to a block kept in yescrypt's SIMD shuffled word order.
+func salsa20(b []uint32, rounds int) {
+	var x [16]uint32
+	for i := range 16 {
+		x[i*5%16] = b[i]
+	}
+	for i := 0; i < rounds; i += 2 {
+		x[4] ^= bits.RotateLeft32(x[0]+x[12], 7)
+		x[8] ^= bits.RotateLeft32(x[4]+x[0], 9)
+		x[12] ^= bits.RotateLeft32(x[8]+x[4], 13)
+		x[0] ^= bits.RotateLeft32(x[12]+x[8], 18)
+		x[9] ^= bits.RotateLeft32(x[5]+x[1], 7)
+		x[13] ^= bits.RotateLeft32(x[9]+x[5], 9)
+		x[1] ^= bits.RotateLeft32(x[13]+x[9], 13)
+		x[5] ^= bits.RotateLeft32(x[1]+x[13], 18)
+		x[14] ^= bits.RotateLeft32(x[10]+x[6], 7)
+		x[2] ^= bits.RotateLeft32(x[14]+x[10], 9)
+		x[6] ^= bits.RotateLeft32(x[2]+x[14], 13)
+		x[10] ^= bits.RotateLeft32(x[6]+x[2], 18)
+		x[3] ^= bits.RotateLeft32(x[15]+x[11], 7)
+		x[7] ^= bits.RotateLeft32(x[3]+x[15], 9)
+		x[11] ^= bits.RotateLeft32(x[7]+x[3], 13)
+		x[15] ^= bits.RotateLeft32(x[11]+x[7], 18)
+
+		x[1] ^= bits.RotateLeft32(x[0]+x[3], 7)
+		x[2] ^= bits.RotateLeft32(x[1]+x[0], 9)
+		x[3] ^= bits.RotateLeft32(x[2]+x[1], 13)
+		x[0] ^= bits.RotateLeft32(x[3]+x[2], 18)
+		x[6] ^= bits.RotateLeft32(x[5]+x[4], 7)
+		x[7] ^= bits.RotateLeft32(x[6]+x[5], 9)
+		x[4] ^= bits.RotateLeft32(x[7]+x[6], 13)
+		x[5] ^= bits.RotateLeft32(x[4]+x[7], 18)
+		x[11] ^= bits.RotateLeft32(x[10]+x[9], 7)
+		x[8] ^= bits.RotateLeft32(x[11]+x[10], 9)
+		x[9] ^= bits.RotateLeft32(x[8]+x[11], 13)
+		x[10] ^= bits.RotateLeft32(x[9]+x[8], 18)
+		x[12] ^= bits.RotateLeft32(x[15]+x[14], 7)
+		x[13] ^= bits.RotateLeft32(x[12]+x[15], 9)
+		x[14] ^= bits.RotateLeft32(x[13]+x[12], 13)
+		x[15] ^= bits.RotateLeft32(x[14]+x[13], 18)
+	}
+	for i := range 16 {
+		b[i] += x[i*5%16]
+	}
+}
+
+func blkxor(dst, src []uint32) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
+
+// blockmixSalsa8 is scrypt's BlockMix with Salsa20/8, writing the result to y.
+func blockmixSalsa8(b, y []uint32, r int) {
+	var x [16]uint32
+	copy(x[:], b[(2*r-1)*16:])
+	for i := 0; i < 2*r; i += 2 {
+		blkxor(x[:], b[i*16:i*16+16])
+		salsa20(x[:], 8)
+		copy(y[i*8:], x[:])
+		blkxor(x[:], b[i*16+16:i*16+32])
+		salsa20(x[:], 8)
+		copy(y[i*8+r*16:], x[:])
+	}
+}
+
+func (ctx *pwxformCtx) pwxform(b []uint32) {
+	w := ctx.w
+	for i := range pwxRounds {
+		for j := range pwxGather {
+			xl := b[j*pwxSimple*2]
+			xh := b[j*pwxSimple*2+1]
+			p0 := ctx.s0[(xl&pwxSMask)/4:]
+			p1 := ctx.s1[(xh&pwxSMask)/4:]
+			for k := range pwxSimple {
+				s0 := uint64(p0[k*2+1])<<32 | uint64(p0[k*2])
+				s1 := uint64(p1[k*2+1])<<32 | uint64(p1[k*2])
+				lo := b[(j*pwxSimple+k)*2]
+				hi := b[(j*pwxSimple+k)*2+1]
+				x := (uint64(hi)*uint64(lo) + s0) ^ s1
+				b[(j*pwxSimple+k)*2] = uint32(x)
+				b[(j*pwxSimple+k)*2+1] = uint32(x >> 32)
+				if i != 0 && i != pwxRounds-1 {
+					ctx.s2[w*2] = uint32(x)
+					ctx.s2[w*2+1] = uint32(x >> 32)
+					w++
+				}
+			}
+		}
+	}
+	ctx.s0, ctx.s1, ctx.s2 = ctx.s2, ctx.s0, ctx.s1
+	ctx.w = w & ((1<<pwxSwidth)*pwxSimple - 1)
+}
+
+// blockmixPwxform is yescrypt's BlockMix, transforming b in place.
+func (ctx *pwxformCtx) blockmix(b []uint32, r int) {
+	r1 := 128 * r / (pwxWords * 4)
+	var x [pwxWords]uint32
+	copy(x[:], b[(r1-1)*pwxWords:])
+	for i := range r1 {
+		if r1 > 1 {
+			blkxor(x[:], b[i*pwxWords:(i+1)*pwxWords])
+		}
+		ctx.pwxform(x[:])
+		copy(b[i*pwxWords:], x[:])
+	}
+	i := (r1 - 1) * pwxWords / 16
+	salsa20(b[i*16:i*16+16], 2)
+	for i++; i < 2*r; i++ {
+		blkxor(b[i*16:i*16+16], b[(i-1)*16:i*16])
+		salsa20(b[i*16:i*16+16], 2)
+	}
+}
+
+func integerify(x []uint32, r int) uint64 {
+	last := x[(2*r-1)*16:]
+	return uint64(last[13])<<32 | uint64(last[0])
+}
+
+func p2floor(x uint64) uint64 {
+	for x&(x-1) != 0 {
+		x &= x - 1
+	}
+	return x
+}
+
+func wrap(x, i uint64) uint64 {
+	n := p2floor(i)
+	return (x & (n - 1)) + (i - n)
+}
+
+func loadBlock(x []uint32, b []byte, r int) {
+	for k := 0; k < 2*r; k++ {
+		for i := range 16 {
+			x[k*16+i] = binary.LittleEndian.Uint32(b[(k*16+i*5%16)*4:])
+		}
+	}
+}
+
+func storeBlock(b []byte, x []uint32, r int) {
+	for k := 0; k < 2*r; k++ {
+		for i := range 16 {
+			binary.LittleEndian.PutUint32(b[(k*16+i*5%16)*4:], x[k*16+i])
+		}
+	}
+}
+
+func (ctx *pwxformCtx) mix(x, y []uint32, r int) {
+	if ctx == nil {
+		blockmixSalsa8(x, y, r)
+		copy(x, y)
+		return
+	}
+	ctx.blockmix(x, r)
+}
+
+func smix1(b []byte, r int, n uint64, rw bool, v []uint32, xy []uint32, ctx *pwxformCtx) {
+	s := 32 * r
+	x, y := xy[:s], xy[s:]
+	loadBlock(x, b, r)
+	for i := uint64(0); i < n; i++ {
+		copy(v[i*uint64(s):], x)
+		if rw && i > 1 {
+			j := wrap(integerify(x, r), i)
+			blkxor(x, v[j*uint64(s):(j+1)*uint64(s)])
+		}
+		ctx.mix(x, y, r)
+	}
+	storeBlock(b, x, r)
+}
+
+func smix2(b []byte, r int, n, nloop uint64, rw bool, v []uint32, xy []uint32, ctx *pwxformCtx) {
+	if nloop == 0 {
+		return
+	}
+	s := 32 * r
+	x, y := xy[:s], xy[s:]
+	loadBlock(x, b, r)
+	for i := uint64(0); i < nloop; i++ {
+		j := integerify(x, r) & (n - 1)
+		vj := v[j*uint64(s) : (j+1)*uint64(s)]
+		blkxor(x, vj)
+		if rw {
+			copy(vj, x)
+		}
+		ctx.mix(x, y, r)
+	}
+	storeBlock(b, x, r)
+}
+
+// yescryptBody is yescrypt_kdf_body for p = 1 and t = 0, returning the 32 byte hash.
+func yescryptBody(password, salt []byte, n uint64, r int, prehash bool) []byte {
+	key := []byte("yescrypt-prehash")
+	if !prehash {
+		key = key[:8]
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(password)
+	password = mac.Sum(nil)
+
+	b := pbkdf2.Key(password, salt, 1, 128*r, sha256.New)
+	password = append([]byte(nil), b[:32]...)
+
+	v := make([]uint32, 32*r*int(n))
+	xy := make([]uint32, 64*r)
+	s := make([]uint32, pwxSWords)
+
+	smix1(b, 1, pwxSWords*4/128, false, s, xy, nil)
+	ctx := &pwxformCtx{s2: s[:pwxSWords/3], s1: s[pwxSWords/3 : pwxSWords/3*2], s0: s[pwxSWords/3*2:]}
+	mac = hmac.New(sha256.New, b[128*r-64:128*r])
+	mac.Write(password)
+	password = mac.Sum(nil)
+
+	nloop := (n + 2) / 3
+	nloop = (nloop + 1) &^ 1
+	smix1(b, r, n, true, v, xy, ctx)
+	smix2(b, r, p2floor(n), nloop, true, v, xy, ctx)
+
+	dk := pbkdf2.Key(password, b, 1, yescryptHashSize, sha256.New)
+	if prehash {
+		return dk
+	}
+	mac = hmac.New(sha256.New, dk)
+	mac.Write([]byte("Client Key"))
+	stored := sha256.Sum256(mac.Sum(nil))
+	return stored[:]
+}
+
+// yescryptKey derives the 32 byte yescrypt hash with the default flavor, N = 2^nLog2 and p = 1.
+func yescryptKey(password, salt []byte, nLog2, r int) []byte {
+	n := uint64(1) << nLog2
+	if n >= 0x100 && n*uint64(r) >= 0x20000 {
+		password = yescryptBody(password, salt, n>>6, r, true)
+	}
+	return yescryptBody(password, salt, n, r, false)
+}
+
+// encode64Uint32 writes yescrypt's variable length encoding of a parameter value.
+func encode64Uint32(out *strings.Builder, value, minimum uint32) {
+	value -= minimum
+	start, end, chars, shift := uint32(0), uint32(47), 1, 0
+	for {
+		count := (end + 1 - start) << shift
+		if value < count {
+			break
+		}
+		start = end + 1
+		end = start + (62-end)/2
+		value -= count
+		chars++
+		shift += 6
+	}
+	out.WriteByte(cryptAlphabet[start+(value>>shift)])
+	for ; chars > 1; chars-- {
+		shift -= 6
+		out.WriteByte(cryptAlphabet[(value>>shift)&0x3f])
+	}
+}
+
+// encode64 writes data in yescrypt's little endian crypt base64.
+func encode64(out *strings.Builder, data []byte) {
+	for i := 0; i < len(data); i += 3 {
+		var value uint
+		n := 0
+		for ; n < 3 && i+n < len(data); n++ {
+			value |= uint(data[i+n]) << (8 * n)
+		}
+		cryptEncode(out, value, (n*8+5)/6)
+	}
+}
+
+// decode64 is the inverse of encode64, rejecting non-canonical input.
+func decode64(data string) ([]byte, error) {
+	var out []byte
+	for len(data) > 0 {
+		group := data[:min(4, len(data))]
+		data = data[len(group):]
+		var value uint32
+		for i := range len(group) {
+			c := strings.IndexByte(cryptAlphabet, group[i])
+			if c < 0 {
+				return nil, errors.New("invalid character")
+			}
+			value |= uint32(c) << (6 * i)
+		}
+		if len(group) == 1 {
+			return nil, errors.New("truncated group")
+		}
+		n := len(group) * 6 / 8
+		for range n {
+			out = append(out, byte(value))
+			value >>= 8
+		}
+		if value != 0 {
+			return nil, errors.New("non-canonical encoding")
+		}
+	}
+	return out, nil
+}
+
+// yescryptCrypt returns the `$y$` crypt string of password, as produced by libxcrypt.
+func yescryptCrypt(password string, salt []byte, nLog2, r int) string {
+	var out strings.Builder
+	out.WriteString("$y$")
+	encode64Uint32(&out, 2+(yescryptFlavor>>2), 0)
+	encode64Uint32(&out, uint32(nLog2), 1)
+	encode64Uint32(&out, uint32(r), 1)
+	out.WriteString("$")
+	encode64(&out, salt)
+	out.WriteString("$")
+	encode64(&out, yescryptKey([]byte(password), salt, nLog2, r))
+	return out.String()
+}
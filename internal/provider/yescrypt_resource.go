@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+var (
+	_ resource.Resource = &YescryptResource{}
+)
+
+func NewYescryptResource() resource.Resource {
+	return &YescryptResource{}
+}
+
+type YescryptResource struct{}
+
+func (r *YescryptResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_yescrypt"
+}
+
+func (r *YescryptResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "yescrypt password hash in the `$y$` crypt format, the `/etc/shadow` default of current Linux distributions.",
+
+		Attributes: map[string]schema.Attribute{
+			"password": schema.StringAttribute{
+				MarkdownDescription: "The password to hash.",
+				Required:            true,
+				Sensitive:           true,
+			},
+			"n_log2": schema.Int64Attribute{
+				MarkdownDescription: "Base 2 logarithm of the block count N. Memory use is `128 * r * 2^n_log2` bytes. Defaults to `12`, as for libxcrypt's default cost.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(12),
+				Validators: []validator.Int64{
+					int64validator.Between(2, 20),
+				},
+			},
+			"r": schema.Int64Attribute{
+				MarkdownDescription: "Block size parameter. Defaults to `32`.",
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(32),
+				Validators: []validator.Int64{
+					int64validator.Between(1, 64),
+				},
+			},
+			"salt": schema.StringAttribute{
+				MarkdownDescription: "The salt in crypt base64, as found between the third and fourth `$` of a `$y$` hash. Generated from 16 random bytes when not set, and kept across updates.",
+				Optional:            true,
+				Computed:            true,
+				Sensitive:           true,
+			},
+			"result": schema.StringAttribute{
+				MarkdownDescription: "The `$y$` hash.",
+				Computed:            true,
+				Sensitive:           true,
+			},
+		},
+	}
+}
+
+type YescryptResourceData struct {
+	Password types.String `tfsdk:"password"`
+	NLog2    types.Int64  `tfsdk:"n_log2"`
+	R        types.Int64  `tfsdk:"r"`
+	Salt     types.String `tfsdk:"salt"`
+	Result   types.String `tfsdk:"result"`
+}
+
+// hashYescrypt fills in salt and result of data.
+func hashYescrypt(data *YescryptResourceData, diags *diag.Diagnostics) {
+	var salt []byte
+	if data.Salt.IsUnknown() || data.Salt.IsNull() {
+		var err error
+		salt, err = newSalt(16)
+		if err != nil {
+			diags.AddError("Salt Error", err.Error())
+			return
+		}
+		var encoded strings.Builder
+		encode64(&encoded, salt)
+		data.Salt = types.StringValue(encoded.String())
+	} else {
+		var err error
+		salt, err = decode64(data.Salt.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("salt"), "Invalid Salt", "The salt is not canonical crypt base64: "+err.Error()+".")
+			return
+		}
+	}
+
+	data.Result = types.StringValue(yescryptCrypt(data.Password.ValueString(), salt, int(data.NLog2.ValueInt64()), int(data.R.ValueInt64())))
+}
+
+func (r *YescryptResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan YescryptResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	hashYescrypt(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *YescryptResource) Read(_ context.Context, _ resource.ReadRequest, _ *resource.ReadResponse) {
+}
+
+func (r *YescryptResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state YescryptResourceData
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Salt.IsUnknown() {
+		plan.Salt = state.Salt
+	}
+	hashYescrypt(&plan, &resp.Diagnostics)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+}
+
+func (r *YescryptResource) Delete(ctx context.Context, _ resource.DeleteRequest, resp *resource.DeleteResponse) {
+	resp.State.RemoveResource(ctx)
+}
@@ -0,0 +1,50 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+)
+
+func TestAccYescryptResource(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { testAccPreCheck(t) },
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "pbkdf2_yescrypt" "test" {
+  password = "password"
+  salt     = "saltsaltsaltsalt"
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("pbkdf2_yescrypt.test", "result", "$y$j9T$saltsaltsaltsalt$Uxvkjnhdr/2B6SINV1mXACdXVbd5kc899ms5aqhxMQD"),
+				),
+			},
+			{
+				Config: `
+resource "pbkdf2_yescrypt" "test" {
+  password = "password"
+  n_log2   = 8
+}
+`,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					// The salt is kept when it is no longer configured.
+					resource.TestMatchResourceAttr("pbkdf2_yescrypt.test", "result", regexp.MustCompile(`^\$y\$j5T\$saltsaltsaltsalt\$[./0-9A-Za-z]{43}$`)),
+				),
+			},
+			{
+				// libxcrypt rejects hashes with N = 2^1.
+				Config: `
+resource "pbkdf2_yescrypt" "test" {
+  password = "password"
+  n_log2   = 1
+}
+`,
+				ExpectError: regexp.MustCompile(`n_log2`),
+			},
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package provider
+
+import "testing"
+
+func TestYescryptCrypt(t *testing.T) {
+	// Expected values produced by libxcrypt's crypt(3).
+	cases := []struct {
+		password string
+		salt     string
+		nLog2    int
+		r        int
+		expected string
+	}{
+		{"password", "saltsaltsaltsalt", 12, 32, "$y$j9T$saltsaltsaltsalt$Uxvkjnhdr/2B6SINV1mXACdXVbd5kc899ms5aqhxMQD"},
+		{"password", "saltsaltsaltsalt", 11, 32, "$y$j8T$saltsaltsaltsalt$3AmdJlblcRLUZGcfZ1G1y.qsYNkOwS96sZWcNOPhzb4"},
+		{"password", "saltsaltsaltsalt", 8, 32, "$y$j5T$saltsaltsaltsalt$cxF.3g293y3.Z8R8fZ99hyyBXMGY12HNeVpq7Tc0wvB"},
+		{"password", "saltsaltsaltsalt", 12, 18, "$y$j9F$saltsaltsaltsalt$Z8hK.wbOxBYd5Kv4T3LD8IEDxyyn5hnHuzZiFnfwb75"},
+		{"password", "saltsaltsaltsalt", 10, 2, "$y$j7/$saltsaltsaltsalt$JwVRcNwQnWy84.yiSkZoaFzeK5InvveWZeQxQuiDW5."},
+		{"", "saltsaltsaltsalt", 12, 32, "$y$j9T$saltsaltsaltsalt$npmzjKb0OjmsMjnYl3f0ooI/H3XJW7wtOyPYuc67xl/"},
+		{"password", "LdJMENpBABJJ3hIHjB1Bi.", 14, 32, "$y$jBT$LdJMENpBABJJ3hIHjB1Bi.$DeK6q5fvDjSW5zHvUD04iA1I9B./VTYRR3I9Kb3in/6"},
+	}
+
+	for _, c := range cases {
+		salt, err := decode64(c.salt)
+		if err != nil {
+			t.Fatalf("decode64(%q): %v", c.salt, err)
+		}
+		if actual := yescryptCrypt(c.password, salt, c.nLog2, c.r); actual != c.expected {
+			t.Errorf("yescryptCrypt(%q, %q, %d, %d) = %q, want %q", c.password, c.salt, c.nLog2, c.r, actual, c.expected)
+		}
+	}
+}
+
+func TestDecode64RejectsNonCanonical(t *testing.T) {
+	for _, salt := range []string{"uv", "a", "ab!c"} {
+		if _, err := decode64(salt); err == nil {
+			t.Errorf("decode64(%q) succeeded", salt)
+		}
+	}
+}
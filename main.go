@@ -3,9 +3,15 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
+	"io"
 	"log"
+	"os"
+	"strings"
+	"time"
 
 	"github.com/appkins/terraform-provider-pbkdf2/internal/provider"
+	"github.com/appkins/terraform-provider-pbkdf2/pkg/pbkdf2kit"
 	"github.com/hashicorp/terraform-plugin-framework/providerserver"
 )
 
@@ -17,6 +23,17 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "bench":
+			bench(os.Args[2:])
+			return
+		case "verify":
+			verify(os.Args[2:])
+			return
+		}
+	}
+
 	var debug bool
 
 	flag.BoolVar(&debug, "debug", false, "set to true to run the provider with support for debuggers like delve")
@@ -33,3 +50,70 @@ func main() {
 		log.Fatal(err.Error())
 	}
 }
+
+// bench prints recommended iteration counts for this machine.
+func bench(args []string) {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	target := fs.Duration("target", 250*time.Millisecond, "time a single derivation should take")
+	prfs := fs.String("prf", "", "comma separated PRFs to benchmark, all of them by default")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s bench [-target duration] [-prf names]\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+
+	var names []string
+	if *prfs != "" {
+		names = strings.Split(*prfs, ",")
+	}
+	if err := provider.Bench(os.Stdout, *target, names); err != nil {
+		log.Fatal(err.Error())
+	}
+}
+
+// verify checks a hash against a candidate password read from stdin. It exits with 0 on a match,
+// 1 on a mismatch and 2 when the hash can't be checked. Like the provider, it reads the pepper from
+// PBKDF2_PEPPER and the derivation context from PBKDF2_DERIVATION_CONTEXT, so neither shows up in the process list.
+func verify(args []string) {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	hash := fs.String("hash", "", "the hash to check")
+	iterations := fs.Int("iterations", 100000, "iterations of a <salt>:<key> hash, which doesn't record them")
+	prf := fs.String("prf", "hmac-sha256", "PRF of a <salt>:<key> hash, which doesn't record it")
+	pepperMode := fs.String("pepper-mode", "hmac", "how the pepper of PBKDF2_PEPPER is mixed into the password, hmac or concat")
+	preHash := fs.Bool("pre-hash", false, "derive from the SHA-512 digest of the password, as for pre_hash")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s verify -hash hash [-iterations n] [-prf name] [-pepper-mode mode] [-pre-hash] < password\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	_ = fs.Parse(args)
+	if *hash == "" {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	// Reading the password from stdin keeps it out of the shell history and process list.
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	password := strings.TrimSuffix(strings.TrimSuffix(string(input), "\n"), "\r")
+
+	match, err := pbkdf2kit.VerifyParams(*hash, password, pbkdf2kit.Params{
+		PRF:        *prf,
+		Iterations: *iterations,
+		PreHash:    *preHash,
+		Pepper:     os.Getenv("PBKDF2_PEPPER"),
+		PepperMode: *pepperMode,
+		Context:    os.Getenv("PBKDF2_DERIVATION_CONTEXT"),
+	})
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid hash: "+err.Error())
+		os.Exit(2)
+	}
+	if !match {
+		fmt.Println("no match")
+		os.Exit(1)
+	}
+	fmt.Println("match")
+}
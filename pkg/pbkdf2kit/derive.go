@@ -0,0 +1,70 @@
+package pbkdf2kit
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+)
+
+// Params are the inputs of a derivation besides the password and salt.
+type Params struct {
+	// PRF is the name of the pseudorandom function, DefaultPRF when unknown.
+	PRF        string
+	Iterations int
+	// KeyLength is the number of bytes to derive, the output size of PRF when zero.
+	KeyLength int
+	// PreHash derives from the SHA-512 digest of the password.
+	PreHash bool
+	// Pepper is mixed into the password as selected by PepperMode, "hmac" or "concat".
+	Pepper     string
+	PepperMode string
+	// Context is prefixed to the salt, length prefixed, as the purpose of NIST SP 800-132 section 5.1.
+	Context string
+}
+
+// Derive derives the key of password and salt. It fails when the password doesn't fit the key of a keyed PRF.
+func Derive(params Params, password string, salt []byte) ([]byte, error) {
+	p, ok := LookupPRF(params.PRF)
+	if !ok {
+		p, _ = LookupPRF(DefaultPRF)
+	}
+	keyLen := params.KeyLength
+	if keyLen == 0 {
+		keyLen = p.Size
+	}
+	return Key(p, PreparePassword(params, password), ContextSalt(params.Context, salt), params.Iterations, keyLen)
+}
+
+// PreparePassword turns the password into the PBKDF2 input: pre-hashed with SHA-512 when
+// PreHash is set, then mixed with the pepper as selected by PepperMode.
+func PreparePassword(params Params, password string) []byte {
+	input := []byte(password)
+	if params.PreHash {
+		digest := sha512.Sum512(input)
+		input = digest[:]
+	}
+	if params.Pepper == "" {
+		return input
+	}
+	if params.PepperMode == "concat" {
+		return append(input, params.Pepper...)
+	}
+	p, ok := LookupPRF(params.PRF)
+	if !ok {
+		p, _ = LookupPRF(DefaultPRF)
+	}
+	mac := hmac.New(p.Hash, []byte(params.Pepper))
+	mac.Write(input)
+	return mac.Sum(nil)
+}
+
+// ContextSalt prefixes the salt with the length prefixed derivation context, the
+// purpose || random value construction of NIST SP 800-132 section 5.1.
+func ContextSalt(context string, salt []byte) []byte {
+	if context == "" {
+		return salt
+	}
+	prefixed := binary.BigEndian.AppendUint32(nil, uint32(len(context)))
+	prefixed = append(prefixed, context...)
+	return append(prefixed, salt...)
+}
@@ -0,0 +1,90 @@
+package pbkdf2kit
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	cases := map[string]struct {
+		params   Params
+		expected string
+	}{
+		"plain": {
+			params:   Params{PRF: "hmac-sha256", Iterations: 1000},
+			expected: "f275fb870144cc807c68f6a325360af3078741ce4d833d2915500abd2bb88d00",
+		},
+		"context": {
+			params:   Params{PRF: "hmac-sha256", Iterations: 1000, Context: "app"},
+			expected: "b09725079537a1067dbd89d98cb9ab6d3d311e6c96ee333f12edd0148cddbf44",
+		},
+		"hmac pepper": {
+			params:   Params{PRF: "hmac-sha512", Iterations: 1000, KeyLength: 16, Pepper: "pepper", PepperMode: "hmac"},
+			expected: "03e65ae60e31749c2990751479be8d6f",
+		},
+		"pre-hash and concat pepper": {
+			params:   Params{PRF: "hmac-sha256", Iterations: 1000, PreHash: true, Pepper: "pepper", PepperMode: "concat"},
+			expected: "1c9f411245efbfa64f04cf0f279452d065bc09891df7f09868b8c02578ef804c",
+		},
+		"sha1": {
+			params:   Params{PRF: "sha1", Iterations: 1000},
+			expected: "d855b0fe80bb4d092c922cc2fbcd655a598500c3",
+		},
+		"sha224": {
+			params:   Params{PRF: "hmac-sha224", Iterations: 1000},
+			expected: "7bf42c8159727c41acd7009ec369faa51b440f1186d74296ca2d53d0",
+		},
+		"sha384": {
+			params:   Params{PRF: "hmac-sha384", Iterations: 1000},
+			expected: "563d193c3816e6e136358290e1f9dfb28d4e79aab7fc079e6ca6a1a8696e53c976ffefdeb842269edcdec104c4e1a376",
+		},
+		"sha3-256": {
+			params:   Params{PRF: "hmac-sha3-256", Iterations: 1000},
+			expected: "1b39d66367248998ff16bf4d5a53cdbb7ab9797f25e934c38a8f0e7d361c70fe",
+		},
+		"sha3-512": {
+			params: Params{PRF: "hmac-sha3-512", Iterations: 1000},
+			expected: "e179f4e3021903448e0fb91ae175d7e77af9d41b51ca28dfac5747b02f0f52f7" +
+				"8ae1db21a5a519244c53d534ad3b899c8d531d200de5cfa1f6e2651649f45813",
+		},
+		"hmac blake2b": {
+			params: Params{PRF: "blake2b", Iterations: 1000},
+			expected: "b4b5551d96da08db36d66a890057a7f5e7ebe808f78dfb13b8de3668e0531a82" +
+				"01d30b61f278855fa69386077b2d7c866429b3521ca84e849cd3417b41f74f1c",
+		},
+		"keyed blake2b": {
+			params:   Params{PRF: "keyed-blake2b-256", Iterations: 1000},
+			expected: "40ec6f5102cf4221e127085427a9d1228b91f7b294929a589dc21b5a1b96570d",
+		},
+		"keyed blake2b with two blocks": {
+			params: Params{PRF: "keyed-blake2b-512", Iterations: 1000, KeyLength: 80},
+			expected: "c93310e2ef15a85ed7598c9ddfde4126af44f9828adde69d14eb43af1e7e71e1" +
+				"098446dd38045674ce21a4671092223e8f398976edcba67072f541f68287ddcaecbdefcc46df12596500fc8d10d2d36b",
+		},
+	}
+
+	for name, c := range cases {
+		dk, err := Derive(c.params, "password", []byte("saltsaltsaltsalt"))
+		if err != nil {
+			t.Errorf("%s: %s", name, err)
+		} else if actual := hex.EncodeToString(dk); actual != c.expected {
+			t.Errorf("%s: got %s, want %s", name, actual, c.expected)
+		}
+	}
+}
+
+func TestDerive_keyedLongPassword(t *testing.T) {
+	password := strings.Repeat("x", 100)
+	if _, err := Derive(Params{PRF: "keyed-blake2b-256", Iterations: 1000}, password, []byte("saltsaltsaltsalt")); err == nil {
+		t.Error("expected an error for a password longer than the BLAKE2b key")
+	}
+
+	dk, err := Derive(Params{PRF: "keyed-blake2b-256", Iterations: 1000, PreHash: true}, password, []byte("saltsaltsaltsalt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual := hex.EncodeToString(dk); actual != "d3ecc35334c2cb184d111563f23a962c9e5e42e4bb2b50f3c09d14303d2b6302" {
+		t.Errorf("got %s", actual)
+	}
+}
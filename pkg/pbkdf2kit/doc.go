@@ -0,0 +1,4 @@
+// Package pbkdf2kit holds the PBKDF2 derivation, output formats and hash parsing of the
+// pbkdf2 Terraform provider, so other tools can produce and check exactly the values
+// Terraform does.
+package pbkdf2kit
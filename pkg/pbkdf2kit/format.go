@@ -0,0 +1,112 @@
+package pbkdf2kit
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// Material is the derived key along with what it was derived from, as passed to formats.
+type Material struct {
+	Iterations int
+	Salt       []byte
+	Key        []byte
+	// HashAlgorithm is the bare hash name of the PRF, such as `sha256`, empty for PRFs that have none.
+	HashAlgorithm string
+	// Password is the password the key derives from, left empty by callers that must not render it.
+	Password string
+}
+
+// KeyLength is the length of Key in bytes.
+func (m Material) KeyLength() int {
+	return len(m.Key)
+}
+
+// SaltLength is the length of Salt in bytes.
+func (m Material) SaltLength() int {
+	return len(m.Salt)
+}
+
+// DefaultFormat is the format template of keys that don't choose one, `<b64 salt>:<b64 key>`.
+const DefaultFormat = "{{ printf \"%s:%s\" (b64enc .Salt) (b64enc .Key) }}"
+
+func bin(len int, data int) string {
+	bs := make([]byte, 8)
+	binary.BigEndian.PutUint64(bs, uint64(data))
+	return string(bs[8-len:])
+}
+
+func b64enc(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
+
+// trunc keeps the first length characters of s, or the last -length when length is negative, like Sprig's trunc.
+func trunc(length int, s string) string {
+	switch {
+	case length >= 0 && len(s) > length:
+		return s[:length]
+	case length < 0 && len(s) > -length:
+		return s[len(s)+length:]
+	}
+	return s
+}
+
+// formatFuncs are the functions of format templates besides the text/template builtins.
+var formatFuncs = template.FuncMap{
+	"bin":       bin,
+	"b64enc":    b64enc,
+	"b64rawenc": base64.RawStdEncoding.EncodeToString,
+	"b64urlenc": base64.RawURLEncoding.EncodeToString,
+	"b32enc":    base32.StdEncoding.EncodeToString,
+	"hexenc":    hex.EncodeToString,
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"trunc":     trunc,
+}
+
+// FormatFuncNames lists the functions of format templates besides the text/template builtins.
+func FormatFuncNames() []string {
+	names := make([]string, 0, len(formatFuncs))
+	for name := range formatFuncs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatTemplate renders m with a text/template format. Besides the template builtins it
+// has `bin <bytes> <int>` for big endian integers, `b64enc`, `b64rawenc` (unpadded),
+// `b64urlenc` (URL-safe and unpadded), `b32enc` and `hexenc` to encode bytes, and
+// `upper`, `lower` and `trunc <length> <string>` to adjust strings.
+func FormatTemplate(format string, m Material) (string, error) {
+	var key bytes.Buffer
+	formatTemplate := template.New("format")
+	formatTemplate.Funcs(formatFuncs)
+	_, err := formatTemplate.Parse(format)
+	if err != nil {
+		return "", err
+	}
+	err = formatTemplate.Execute(&key, m)
+	if err != nil {
+		return "", err
+	}
+	return key.String(), nil
+}
+
+// ValidateFormat renders format against placeholder material of the largest supported lengths, so syntax
+// errors and unknown fields and functions are found before anything is derived.
+func ValidateFormat(format string) error {
+	_, err := FormatTemplate(format, Material{
+		Iterations:    1,
+		Salt:          make([]byte, 1024),
+		Key:           make([]byte, 1024),
+		HashAlgorithm: "sha256",
+		Password:      "password",
+	})
+	return err
+}
@@ -0,0 +1,56 @@
+package pbkdf2kit
+
+import "testing"
+
+func TestFormatTemplate(t *testing.T) {
+	m := Material{
+		Iterations: 1000,
+		Salt:       []byte("0123456789abcdef"),
+		Key:        []byte{0xde, 0xad, 0xbe, 0xef},
+
+		HashAlgorithm: "sha256",
+		Password:      "one",
+	}
+
+	cases := map[string]string{
+		DefaultFormat: "MDEyMzQ1Njc4OWFiY2RlZg==:3q2+7w==",
+		`{{ bin 4 .Iterations | printf "%x" }}${{ b64enc .Key }}`: "000003e8$3q2+7w==",
+		`{{ hexenc .Key }}`:             "deadbeef",
+		`{{ hexenc .Key | upper }}`:     "DEADBEEF",
+		`{{ b64rawenc .Key }}`:          "3q2+7w",
+		`{{ b64urlenc .Key }}`:          "3q2-7w",
+		`{{ b32enc .Key }}`:             "32W353Y=",
+		`{{ b32enc .Key | lower }}`:     "32w353y=",
+		`{{ hexenc .Salt | trunc 8 }}`:  "30313233",
+		`{{ hexenc .Salt | trunc -4 }}`: "6566",
+		`{{ trunc 64 (hexenc .Key) }}`:  "deadbeef",
+		`pbkdf2_{{ .HashAlgorithm }}${{ .Iterations }}${{ .SaltLength }}${{ .KeyLength }}`: "pbkdf2_sha256$1000$16$4",
+		`{{ .Password }}`: "one",
+	}
+	for format, expected := range cases {
+		actual, err := FormatTemplate(format, m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual != expected {
+			t.Errorf("%s: got %q, want %q", format, actual, expected)
+		}
+	}
+
+	if _, err := FormatTemplate("{{ .Missing }}", m); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{DefaultFormat, `{{ index .Key 63 | printf "%02x" }}`, `{{ .HashAlgorithm | upper }}`} {
+		if err := ValidateFormat(format); err != nil {
+			t.Errorf("%s: %v", format, err)
+		}
+	}
+	for _, format := range []string{"{{ .Missing }}", "{{ sha1 .Key }}", "{{ b64enc .Salt", `{{ trunc "a" .Password }}`} {
+		if err := ValidateFormat(format); err == nil {
+			t.Errorf("%s: expected an error", format)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package pbkdf2kit
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParsedHash holds the parameters recovered from a formatted PBKDF2 hash.
+type ParsedHash struct {
+	HashAlgorithm string
+	Iterations    int
+	Salt          []byte
+	Key           []byte
+}
+
+// ParseHash understands the modular crypt style layouts of PBKDF2 hashes:
+// PHC and passlib (`$pbkdf2-sha256$29000$<salt>$<key>`) as well as Django
+// (`pbkdf2_sha256$600000$<salt>$<key>`). Errors never quote the input, as a
+// misplaced password could end up there.
+func ParseHash(hash string) (ParsedHash, error) {
+	var parsed ParsedHash
+
+	if rest, ok := strings.CutPrefix(hash, "$"); ok {
+		parts := strings.Split(rest, "$")
+		if len(parts) != 4 {
+			return parsed, fmt.Errorf("expected $pbkdf2-<hash>$<iterations>$<salt>$<key>")
+		}
+		algorithm, ok := strings.CutPrefix(parts[0], "pbkdf2")
+		if !ok {
+			return parsed, fmt.Errorf("unsupported scheme, expected pbkdf2 or pbkdf2-<hash>")
+		}
+		parsed.HashAlgorithm = strings.TrimPrefix(algorithm, "-")
+		if parsed.HashAlgorithm == "" {
+			parsed.HashAlgorithm = "sha1"
+		}
+		iterations, err := strconv.Atoi(strings.TrimPrefix(parts[1], "i="))
+		if err != nil || iterations < 1 {
+			return parsed, fmt.Errorf("iterations are not a positive number")
+		}
+		parsed.Iterations = iterations
+		if parsed.Salt, err = ab64dec(parts[2]); err != nil {
+			return parsed, fmt.Errorf("salt is not valid base64")
+		}
+		if parsed.Key, err = ab64dec(parts[3]); err != nil || len(parsed.Key) == 0 {
+			return parsed, fmt.Errorf("key is empty or not valid base64")
+		}
+		return parsed, nil
+	}
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 4 {
+		return parsed, fmt.Errorf("unrecognized hash format")
+	}
+	algorithm, ok := strings.CutPrefix(parts[0], "pbkdf2_")
+	if !ok {
+		return parsed, fmt.Errorf("unsupported scheme, expected pbkdf2_<hash>")
+	}
+	parsed.HashAlgorithm = algorithm
+	iterations, err := strconv.Atoi(parts[1])
+	if err != nil || iterations < 1 {
+		return parsed, fmt.Errorf("iterations are not a positive number")
+	}
+	parsed.Iterations = iterations
+	parsed.Salt = []byte(parts[2])
+	if parsed.Key, err = base64.StdEncoding.DecodeString(parts[3]); err != nil || len(parsed.Key) == 0 {
+		return parsed, fmt.Errorf("key is empty or not valid base64")
+	}
+	return parsed, nil
+}
+
+// ab64dec decodes unpadded base64, accepting passlib's `.` in place of `+`.
+func ab64dec(data string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.ReplaceAll(strings.TrimRight(data, "="), ".", "+"))
+}
+
+// ab64enc encodes data in passlib's adapted base64, unpadded with `.` in place of `+`.
+func ab64enc(data []byte) string {
+	return strings.ReplaceAll(base64.RawStdEncoding.EncodeToString(data), "+", ".")
+}
+
+// ParseSaltKey splits the default `<b64 salt>:<b64 key>` result of pbkdf2_key.
+func ParseSaltKey(hash string) ([]byte, []byte, error) {
+	saltStr, keyStr, found := strings.Cut(hash, ":")
+	if !found {
+		return nil, nil, fmt.Errorf("expected <b64 salt>:<b64 key>")
+	}
+	salt, err := base64.StdEncoding.DecodeString(saltStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("salt is not valid base64")
+	}
+	key, err := base64.StdEncoding.DecodeString(keyStr)
+	if err != nil || len(key) == 0 {
+		return nil, nil, fmt.Errorf("key is empty or not valid base64")
+	}
+	return salt, key, nil
+}
@@ -0,0 +1,80 @@
+package pbkdf2kit
+
+import (
+	"testing"
+)
+
+func TestParseHash(t *testing.T) {
+	cases := map[string]struct {
+		hash          string
+		hashAlgorithm string
+		iterations    int
+		saltLength    int
+		keyLength     int
+	}{
+		"passlib": {
+			hash:          "$pbkdf2-sha256$29000$N2YuJ.R4T2XsvVZ2LgWAsA$mxqhdZUvUUNyHMQ8SdjOP6SyqXCQHT7zsAKf6JjtxVM",
+			hashAlgorithm: "sha256",
+			iterations:    29000,
+			saltLength:    16,
+			keyLength:     32,
+		},
+		"passlib sha1": {
+			hash:          "$pbkdf2$131000$AAAAAAAAAAAAAAAAAAAAAA$AAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			hashAlgorithm: "sha1",
+			iterations:    131000,
+			saltLength:    16,
+			keyLength:     20,
+		},
+		"phc": {
+			hash:          "$pbkdf2-sha512$i=600000$AAAAAAAAAAAAAAAAAAAAAA$AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+			hashAlgorithm: "sha512",
+			iterations:    600000,
+			saltLength:    16,
+			keyLength:     32,
+		},
+		"django": {
+			hash:          "pbkdf2_sha256$600000$saltsaltsaltsalt$AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA=",
+			hashAlgorithm: "sha256",
+			iterations:    600000,
+			saltLength:    16,
+			keyLength:     32,
+		},
+	}
+
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			parsed, err := ParseHash(c.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if parsed.HashAlgorithm != c.hashAlgorithm || parsed.Iterations != c.iterations ||
+				len(parsed.Salt) != c.saltLength || len(parsed.Key) != c.keyLength {
+				t.Errorf("unexpected parse result %s/%d/%d/%d", parsed.HashAlgorithm, parsed.Iterations, len(parsed.Salt), len(parsed.Key))
+			}
+		})
+	}
+
+	for _, hash := range []string{"", "c2FsdA==:a2V5", "$bcrypt$1$a$b", "pbkdf2_sha256$many$salt$key",
+		"$pbkdf2-sha256$1000$c2FsdA$", "pbkdf2_sha256$1000$salt$", "$pbkdf2-sha256$0$c2FsdA$a2V5", "pbkdf2_sha256$-1$salt$a2V5"} {
+		if _, err := ParseHash(hash); err == nil {
+			t.Errorf("expected %q to be rejected", hash)
+		}
+	}
+}
+
+func TestParseSaltKey(t *testing.T) {
+	salt, key, err := ParseSaltKey("c2FsdA==:a2V5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(salt) != "salt" || string(key) != "key" {
+		t.Errorf("unexpected parse result %q/%q", salt, key)
+	}
+
+	for _, hash := range []string{"", "c2FsdA==", "c2FsdA==:", "c2FsdA==:!"} {
+		if _, _, err := ParseSaltKey(hash); err == nil {
+			t.Errorf("expected %q to be rejected", hash)
+		}
+	}
+}
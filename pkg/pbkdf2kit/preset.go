@@ -0,0 +1,272 @@
+package pbkdf2kit
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Preset renders a derived key in the stored format of a specific consumer.
+type Preset struct {
+	Name        string
+	Description string
+	// PRF restricts the preset to one pseudorandom function, for consumers that only support one.
+	PRF string
+	// PRFs restricts the preset to a few pseudorandom functions, for consumers that support more than one.
+	PRFs []string
+	// TextSalt marks consumers that store the salt as text, so it must be generated from a printable charset.
+	TextSalt bool
+	// SaltLength and KeyLength are the exact byte lengths the consumer accepts, when it is picky.
+	SaltLength    int
+	KeyLength     int
+	MaxIterations int64
+	// DigestKeyLength marks consumers that always derive keys of the output size of the PRF.
+	DigestKeyLength bool
+	// KeyedPRFs admits PRFs in a hash's native keyed mode, which none of the built-in consumers implement.
+	KeyedPRFs bool
+	Format    func(p PRF, m Material) string
+}
+
+// ParameterSet is a parameter set to check against a preset. Zero values are not checked.
+type ParameterSet struct {
+	PRF        string
+	Iterations int64
+	SaltLength int
+	KeyLength  int
+	// RawSalt reports a salt of random bytes rather than printable characters.
+	RawSalt bool
+}
+
+// Violations describes every constraint of the preset's consumer that params break.
+func (preset Preset) Violations(params ParameterSet) []string {
+	var violations []string
+	if preset.PRF != "" && params.PRF != "" && params.PRF != preset.PRF {
+		violations = append(violations, fmt.Sprintf("requires prf = %q, got %q", preset.PRF, params.PRF))
+	}
+	if len(preset.PRFs) > 0 && params.PRF != "" && !slices.Contains(preset.PRFs, params.PRF) {
+		violations = append(violations, fmt.Sprintf("requires prf to be one of %s, got %q", strings.Join(preset.PRFs, ", "), params.PRF))
+	}
+	if p, ok := LookupPRF(params.PRF); ok && p.Keyed != nil && !preset.KeyedPRFs {
+		violations = append(violations, fmt.Sprintf("only supports HMAC PRFs, got %q", params.PRF))
+	}
+	if preset.MaxIterations != 0 && params.Iterations > preset.MaxIterations {
+		violations = append(violations, fmt.Sprintf("supports at most %d iterations, got %d", preset.MaxIterations, params.Iterations))
+	}
+	if preset.SaltLength != 0 && params.SaltLength != 0 && params.SaltLength != preset.SaltLength {
+		violations = append(violations, fmt.Sprintf("requires a %d byte salt, got %d bytes", preset.SaltLength, params.SaltLength))
+	}
+	if preset.KeyLength != 0 && params.KeyLength != 0 && params.KeyLength != preset.KeyLength {
+		violations = append(violations, fmt.Sprintf("requires a %d byte key, got %d bytes", preset.KeyLength, params.KeyLength))
+	}
+	if p, ok := LookupPRF(params.PRF); ok && preset.DigestKeyLength && params.KeyLength != 0 && params.KeyLength != p.Size {
+		violations = append(violations, fmt.Sprintf("requires a key of the %d byte output size of %s, got %d bytes", p.Size, p.Name, params.KeyLength))
+	}
+	if preset.TextSalt && params.RawSalt {
+		violations = append(violations, "stores the salt as text, so it requires a printable salt, got random bytes")
+	}
+	return violations
+}
+
+// Presets are the supported output formats, extended by RegisterPreset.
+var Presets = []Preset{
+	{
+		Name: "tomcat",
+		Description: "Tomcat's `PBKDF2CredentialHandler`, `<hex salt>$<iterations>$<hex key>`. " +
+			"Configure the handler with the matching `algorithm`, e.g. `PBKDF2WithHmacSHA256`, and `keyLength` in bits, e.g. `256`",
+		Format: func(_ PRF, m Material) string {
+			return hex.EncodeToString(m.Salt) + "$" + strconv.Itoa(m.Iterations) + "$" + hex.EncodeToString(m.Key)
+		},
+	},
+	{
+		Name: "freeradius",
+		Description: "FreeRADIUS `Password-With-Header` value for `rlm_pap`, " +
+			"`{X-PBKDF2}<digest>:<b64 iterations>:<b64 salt>:<b64 key>` with the iteration count as a 32 bit big endian integer",
+		PRFs:          []string{"hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512", "hmac-sha3-256", "hmac-sha3-512"},
+		MaxIterations: math.MaxUint32,
+		Format: func(p PRF, m Material) string {
+			iterations := binary.BigEndian.AppendUint32(nil, uint32(m.Iterations))
+			return "{X-PBKDF2}" + freeradiusDigests[p.Name] + ":" + base64.StdEncoding.EncodeToString(iterations) + ":" +
+				base64.StdEncoding.EncodeToString(m.Salt) + ":" + base64.StdEncoding.EncodeToString(m.Key)
+		},
+	},
+	{
+		Name: "mosquitto",
+		Description: "Mosquitto password file hash as written by `mosquitto_passwd`, `$7$<iterations>$<b64 salt>$<b64 key>`. " +
+			"Prefix it with `<username>:` to form a password file line",
+		PRF:        "hmac-sha512",
+		SaltLength: 12,
+		KeyLength:  64,
+		Format: func(_ PRF, m Material) string {
+			return "$7$" + strconv.Itoa(m.Iterations) + "$" + base64.StdEncoding.EncodeToString(m.Salt) + "$" +
+				base64.StdEncoding.EncodeToString(m.Key)
+		},
+	},
+	{
+		Name: "postgresql_scram",
+		Description: "PostgreSQL `SCRAM-SHA-256` verifier as stored in `pg_authid`, " +
+			"`SCRAM-SHA-256$<iterations>:<b64 salt>$<b64 StoredKey>:<b64 ServerKey>`, accepted as a password by `CREATE ROLE` and `ALTER ROLE`",
+		PRF:       "hmac-sha256",
+		KeyLength: 32,
+		Format:    scramSHA256Verifier,
+	},
+	{
+		Name: "mediawiki",
+		Description: "MediaWiki `user_password` value of the `pbkdf2` password type, `:pbkdf2:<hash>:<iterations>:<key length>:<b64 salt>:<b64 key>`. " +
+			"Configure `$wgPasswordConfig['pbkdf2']` with the matching `algo`, `cost` and `length`",
+		// PHP's hash_pbkdf2 names no BLAKE2b or Streebog algorithm matching the HMAC PRFs.
+		PRFs: []string{"hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512", "hmac-sha3-256", "hmac-sha3-512"},
+		Format: func(p PRF, m Material) string {
+			return ":pbkdf2:" + p.Alias + ":" + strconv.Itoa(m.Iterations) + ":" + strconv.Itoa(len(m.Key)) + ":" +
+				base64.StdEncoding.EncodeToString(m.Salt) + ":" + base64.StdEncoding.EncodeToString(m.Key)
+		},
+	},
+	{
+		Name: "arangodb",
+		Description: "ArangoDB `authData.simple` object of a `_users` document, " +
+			"`{\"method\":\"pbkdf2-sha256\",\"salt\":\"<hex salt>\",\"hash\":\"<hex key>\",\"iterations\":<iterations>}` as JSON",
+		PRF:       "hmac-sha256",
+		KeyLength: 32,
+		Format: func(_ PRF, m Material) string {
+			return fmt.Sprintf(`{"method":"pbkdf2-sha256","salt":%q,"hash":%q,"iterations":%d}`,
+				hex.EncodeToString(m.Salt), hex.EncodeToString(m.Key), m.Iterations)
+		},
+	},
+	{
+		Name:        "phc",
+		Description: "PHC string format, `$pbkdf2-<hash>$i=<iterations>$<b64 salt>$<b64 key>` with unpadded base64. passlib reads the `passlib` preset instead",
+		PRFs:        []string{"hmac-sha1", "hmac-sha256", "hmac-sha512"},
+		Format: func(p PRF, m Material) string {
+			return "$pbkdf2-" + p.Alias + "$i=" + strconv.Itoa(m.Iterations) + "$" + base64.RawStdEncoding.EncodeToString(m.Salt) + "$" +
+				base64.RawStdEncoding.EncodeToString(m.Key)
+		},
+	},
+	{
+		Name: "passlib",
+		Description: "passlib `pbkdf2_sha1`, `pbkdf2_sha256` and `pbkdf2_sha512` hash, `$pbkdf2-<hash>$<iterations>$<ab64 salt>$<ab64 key>` " +
+			"in passlib's adapted base64, with `$pbkdf2$` for SHA-1. passlib derives keys of the output size of `prf`",
+		PRFs:            []string{"hmac-sha1", "hmac-sha256", "hmac-sha512"},
+		DigestKeyLength: true,
+		Format: func(p PRF, m Material) string {
+			ident := "$pbkdf2-" + p.Alias + "$"
+			if p.Name == "hmac-sha1" {
+				ident = "$pbkdf2$"
+			}
+			return ident + strconv.Itoa(m.Iterations) + "$" + ab64enc(m.Salt) + "$" + ab64enc(m.Key)
+		},
+	},
+	{
+		Name: "django",
+		Description: "Django `password` field of the `PBKDF2PasswordHasher` family, `pbkdf2_<hash>$<iterations>$<salt>$<b64 key>` with the salt as is. " +
+			"Django derives keys of the output size of `prf`",
+		PRFs:            []string{"hmac-sha256", "hmac-sha1"},
+		TextSalt:        true,
+		DigestKeyLength: true,
+		Format: func(p PRF, m Material) string {
+			return "pbkdf2_" + p.Alias + "$" + strconv.Itoa(m.Iterations) + "$" + string(m.Salt) + "$" + base64.StdEncoding.EncodeToString(m.Key)
+		},
+	},
+	{
+		Name: "werkzeug",
+		Description: "Werkzeug `generate_password_hash` result as checked by Flask applications, `pbkdf2:<hash>:<iterations>$<salt>$<hex key>` with the salt as is. " +
+			"Werkzeug derives keys of the output size of `prf`",
+		PRFs:            []string{"hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512"},
+		TextSalt:        true,
+		DigestKeyLength: true,
+		Format: func(p PRF, m Material) string {
+			return "pbkdf2:" + p.Alias + ":" + strconv.Itoa(m.Iterations) + "$" + string(m.Salt) + "$" + hex.EncodeToString(m.Key)
+		},
+	},
+	{
+		Name: "ldap_pbkdf2",
+		Description: "OpenLDAP `userPassword` value for the `pw-pbkdf2` module, `{PBKDF2-<HASH>}<iterations>$<ab64 salt>$<ab64 key>` " +
+			"in passlib's adapted base64. The module derives keys of the output size of `prf`",
+		PRFs:            []string{"hmac-sha1", "hmac-sha256", "hmac-sha512"},
+		SaltLength:      16,
+		DigestKeyLength: true,
+		Format: func(p PRF, m Material) string {
+			return "{PBKDF2-" + strings.ToUpper(p.Alias) + "}" + strconv.Itoa(m.Iterations) + "$" + ab64enc(m.Salt) + "$" + ab64enc(m.Key)
+		},
+	},
+	{
+		Name: "aspnet_identity",
+		Description: "ASP.NET Core Identity `PasswordHash` column in the version 3 layout, the base64 encoded " +
+			"`0x01 || <prf id> || <iterations> || <salt length> || <salt> || <key>` with 32 bit big endian integers. Identity rejects salts and keys below 16 bytes",
+		PRFs:          []string{"hmac-sha1", "hmac-sha256", "hmac-sha512"},
+		MaxIterations: math.MaxInt32,
+		Format: func(p PRF, m Material) string {
+			hash := []byte{0x01}
+			hash = binary.BigEndian.AppendUint32(hash, aspnetIdentityPRFs[p.Name])
+			hash = binary.BigEndian.AppendUint32(hash, uint32(m.Iterations))
+			hash = binary.BigEndian.AppendUint32(hash, uint32(len(m.Salt)))
+			hash = append(append(hash, m.Salt...), m.Key...)
+			return base64.StdEncoding.EncodeToString(hash)
+		},
+	},
+}
+
+// aspnetIdentityPRFs are the KeyDerivationPrf values ASP.NET Core Identity stores for each PRF.
+var aspnetIdentityPRFs = map[string]uint32{
+	"hmac-sha1":   0,
+	"hmac-sha256": 1,
+	"hmac-sha512": 2,
+}
+
+// scramSHA256Verifier renders the salted password in m.Key as a SCRAM-SHA-256 verifier.
+func scramSHA256Verifier(_ PRF, m Material) string {
+	storedKey, serverKey := SCRAMKeys(m.Key)
+	return "SCRAM-SHA-256$" + strconv.Itoa(m.Iterations) + ":" + base64.StdEncoding.EncodeToString(m.Salt) + "$" +
+		base64.StdEncoding.EncodeToString(storedKey) + ":" + base64.StdEncoding.EncodeToString(serverKey)
+}
+
+// freeradiusDigests are the names rlm_pap uses for the HMAC digest of each PRF.
+var freeradiusDigests = map[string]string{
+	"hmac-sha1":     "HMACSHA1",
+	"hmac-sha224":   "HMACSHA2+224",
+	"hmac-sha256":   "HMACSHA2+256",
+	"hmac-sha384":   "HMACSHA2+384",
+	"hmac-sha512":   "HMACSHA2+512",
+	"hmac-sha3-256": "HMACSHA3+256",
+	"hmac-sha3-512": "HMACSHA3+512",
+}
+
+// RegisterPreset adds a preset to Presets, e.g. for a proprietary format in a custom build of the provider.
+// Register presets before the provider is served, as its schema lists them.
+func RegisterPreset(preset Preset) error {
+	if preset.Name == "" || preset.Format == nil {
+		return fmt.Errorf("a preset needs a name and a format")
+	}
+	if _, ok := LookupPreset(preset.Name); ok {
+		return fmt.Errorf("preset %q is already registered", preset.Name)
+	}
+	for _, prf := range append([]string{preset.PRF}, preset.PRFs...) {
+		if _, ok := LookupPRF(prf); prf != "" && !ok {
+			return fmt.Errorf("preset %q requires the unknown prf %q", preset.Name, prf)
+		}
+	}
+	Presets = append(Presets, preset)
+	return nil
+}
+
+// LookupPreset finds a preset by name.
+func LookupPreset(name string) (Preset, bool) {
+	for _, preset := range Presets {
+		if preset.Name == name {
+			return preset, true
+		}
+	}
+	return Preset{}, false
+}
+
+// PresetNames lists the names of Presets.
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for _, preset := range Presets {
+		names = append(names, preset.Name)
+	}
+	return names
+}
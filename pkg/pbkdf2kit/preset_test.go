@@ -0,0 +1,168 @@
+package pbkdf2kit
+
+import (
+	"encoding/hex"
+	"strconv"
+	"testing"
+)
+
+func TestFormatPresets(t *testing.T) {
+	data := Material{
+		Iterations: 1000,
+		Salt:       []byte("0123456789abcdef"),
+		Key:        []byte{0xde, 0xad, 0xbe, 0xef},
+	}
+
+	cases := []struct {
+		preset   string
+		prf      string
+		expected string
+	}{
+		{"tomcat", "hmac-sha256", "30313233343536373839616263646566$1000$deadbeef"},
+		{"freeradius", "hmac-sha512", "{X-PBKDF2}HMACSHA2+512:AAAD6A==:MDEyMzQ1Njc4OWFiY2RlZg==:3q2+7w=="},
+		{"mosquitto", "hmac-sha512", "$7$1000$MDEyMzQ1Njc4OWFiY2RlZg==$3q2+7w=="},
+		{"postgresql_scram", "hmac-sha256", "SCRAM-SHA-256$1000:MDEyMzQ1Njc4OWFiY2RlZg==$QgSw6dLFA94UrC3kfatjFPU3PaV7RosuI+2qNpOT/8s=:k7I5tRAuZsGACl2H7yX/u6sNTWn1LjofT9yDHZjAkd0="},
+		{"mediawiki", "hmac-sha512", ":pbkdf2:sha512:1000:4:MDEyMzQ1Njc4OWFiY2RlZg==:3q2+7w=="},
+		{"arangodb", "hmac-sha256", `{"method":"pbkdf2-sha256","salt":"30313233343536373839616263646566","hash":"deadbeef","iterations":1000}`},
+		{"phc", "hmac-sha256", "$pbkdf2-sha256$i=1000$MDEyMzQ1Njc4OWFiY2RlZg$3q2+7w"},
+		{"passlib", "hmac-sha256", "$pbkdf2-sha256$1000$MDEyMzQ1Njc4OWFiY2RlZg$3q2.7w"},
+		{"passlib", "hmac-sha1", "$pbkdf2$1000$MDEyMzQ1Njc4OWFiY2RlZg$3q2.7w"},
+		{"django", "hmac-sha256", "pbkdf2_sha256$1000$0123456789abcdef$3q2+7w=="},
+		{"werkzeug", "hmac-sha256", "pbkdf2:sha256:1000$0123456789abcdef$deadbeef"},
+		{"ldap_pbkdf2", "hmac-sha512", "{PBKDF2-SHA512}1000$MDEyMzQ1Njc4OWFiY2RlZg$3q2.7w"},
+		{"aspnet_identity", "hmac-sha256", "AQAAAAEAAAPoAAAAEDAxMjM0NTY3ODlhYmNkZWberb7v"},
+	}
+
+	for _, c := range cases {
+		preset, ok := LookupPreset(c.preset)
+		if !ok {
+			t.Fatalf("preset %q is not registered", c.preset)
+		}
+		p, _ := LookupPRF(c.prf)
+		if actual := preset.Format(p, data); actual != c.expected {
+			t.Errorf("%s: got %q, want %q", c.preset, actual, c.expected)
+		}
+	}
+}
+
+// TestPasslibPreset renders the known hashes of passlib's own pbkdf2 test vectors.
+func TestPasslibPreset(t *testing.T) {
+	cases := []struct {
+		prf  string
+		salt string
+		hash string
+	}{
+		{"hmac-sha1", "381f9db674845d92bc539720c54fc661", "$pbkdf2$1212$OB.dtnSEXZK8U5cgxU/GYQ$y5LKPOplRmok7CZp/aqVDVg8zGI"},
+		{"hmac-sha256", "e2f8d5f372ca3e3433937d5523813457", "$pbkdf2-sha256$1212$4vjV83LKPjQzk31VI4E0Vw$hsYF68OiOUPdDZ1Fg.fJPeq1h/gXXY7acBp9/6c.tmQ"},
+		{"hmac-sha512", "44763416bdc80cc4953bf4526726f9a3",
+			"$pbkdf2-sha512$1212$RHY0Fr3IDMSVO/RSZyb5ow$eNLfBK.eVozomMr.1gYa17k9B7KIK25NOEshvhrSX.esqY3s.FvWZViXz4KoLlQI.BzY/YTNJOiKc5gBYFYGww"},
+	}
+
+	preset, _ := LookupPreset("passlib")
+	for _, c := range cases {
+		salt, _ := hex.DecodeString(c.salt)
+		key, err := Derive(Params{PRF: c.prf, Iterations: 1212}, "password", salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, _ := LookupPRF(c.prf)
+		if actual := preset.Format(p, Material{Iterations: 1212, Salt: salt, Key: key}); actual != c.hash {
+			t.Errorf("%s: got %q, want %q", c.prf, actual, c.hash)
+		}
+		if _, err := ParseHash(c.hash); err != nil {
+			t.Errorf("%s: %v", c.prf, err)
+		}
+	}
+}
+
+func TestRegisterPreset(t *testing.T) {
+	defer func(presets []Preset) { Presets = presets }(Presets)
+
+	preset := Preset{
+		Name: "custom",
+		Format: func(_ PRF, m Material) string {
+			return "custom$" + strconv.Itoa(m.Iterations)
+		},
+	}
+	if err := RegisterPreset(preset); err != nil {
+		t.Fatal(err)
+	}
+	registered, ok := LookupPreset("custom")
+	if !ok {
+		t.Fatal("preset is not registered")
+	}
+	if actual := registered.Format(PRF{}, Material{Iterations: 1000}); actual != "custom$1000" {
+		t.Errorf("got %q", actual)
+	}
+
+	for _, invalid := range []Preset{
+		preset,
+		{Name: "tomcat", Format: preset.Format},
+		{Name: "nameless"},
+		{Name: "md5", PRF: "hmac-md5", Format: preset.Format},
+	} {
+		if err := RegisterPreset(invalid); err == nil {
+			t.Errorf("%q: expected an error", invalid.Name)
+		}
+	}
+}
+
+func TestPresetViolations_keyedPRF(t *testing.T) {
+	preset, _ := LookupPreset("tomcat")
+	if violations := preset.Violations(ParameterSet{PRF: "keyed-blake2b-256"}); len(violations) != 1 {
+		t.Errorf("expected a keyed PRF violation, got %q", violations)
+	}
+
+	preset.KeyedPRFs = true
+	if violations := preset.Violations(ParameterSet{PRF: "keyed-blake2b-256"}); len(violations) != 0 {
+		t.Errorf("unexpected violations %q", violations)
+	}
+}
+
+func TestPresetViolations_prfs(t *testing.T) {
+	preset, _ := LookupPreset("django")
+	if violations := preset.Violations(ParameterSet{PRF: "hmac-sha1"}); len(violations) != 0 {
+		t.Errorf("unexpected violations %q", violations)
+	}
+	if violations := preset.Violations(ParameterSet{PRF: "hmac-sha512"}); len(violations) != 1 {
+		t.Errorf("expected a prf violation, got %q", violations)
+	}
+}
+
+func TestPresetPRFsNamed(t *testing.T) {
+	for _, name := range []string{"freeradius", "mediawiki"} {
+		preset, _ := LookupPreset(name)
+		for _, prf := range PRFs {
+			allowed := len(preset.Violations(ParameterSet{PRF: prf.Name})) == 0
+			named := freeradiusDigests[prf.Name] != ""
+			if name == "mediawiki" {
+				named = prf.Alias != "" && prf.Alias != "blake2b"
+			}
+			if allowed != named {
+				t.Errorf("%s: %s is allowed %t, but has a hash name %t", name, prf.Name, allowed, named)
+			}
+		}
+	}
+}
+
+func TestPresetViolations_digestKeyLength(t *testing.T) {
+	for _, name := range []string{"django", "werkzeug", "ldap_pbkdf2", "passlib"} {
+		preset, _ := LookupPreset(name)
+		if violations := preset.Violations(ParameterSet{PRF: "hmac-sha256", KeyLength: 32}); len(violations) != 0 {
+			t.Errorf("%s: unexpected violations %q", name, violations)
+		}
+		if violations := preset.Violations(ParameterSet{PRF: "hmac-sha256", KeyLength: 16}); len(violations) != 1 {
+			t.Errorf("%s: expected a key length violation, got %q", name, violations)
+		}
+	}
+}
+
+func TestPresetViolations_textSalt(t *testing.T) {
+	preset, _ := LookupPreset("werkzeug")
+	if violations := preset.Violations(ParameterSet{RawSalt: true}); len(violations) != 1 {
+		t.Errorf("expected a salt violation, got %q", violations)
+	}
+	if violations := preset.Violations(ParameterSet{}); len(violations) != 0 {
+		t.Errorf("unexpected violations %q", violations)
+	}
+}
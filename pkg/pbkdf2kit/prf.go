@@ -0,0 +1,113 @@
+package pbkdf2kit
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"fmt"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/sha3"
+)
+
+// PRF is a pseudorandom function PBKDF2 can be keyed with.
+type PRF struct {
+	// Name is the canonical identity accepted by the prf attribute.
+	Name string
+	// Alias is the bare hash name accepted by the deprecated hash_algorithm attribute, empty for PRFs that have none.
+	Alias string
+	Size  int
+	Hash  func() hash.Hash
+	// Keyed is the hash's native keyed mode, used as the PRF instead of HMAC over Hash when set.
+	Keyed func(key []byte) (hash.Hash, error)
+	// Approved reports whether the PRF is an HMAC with a hash approved by NIST SP 800-132.
+	Approved bool
+}
+
+// DefaultPRF is the PRF of keys that don't choose one.
+const DefaultPRF = "hmac-sha256"
+
+// PRFs are the supported pseudorandom functions.
+var PRFs = []PRF{
+	{Name: "hmac-sha256", Alias: "sha256", Size: 32, Hash: sha256.New, Approved: true},
+	{Name: "hmac-sha512", Alias: "sha512", Size: 64, Hash: sha512.New, Approved: true},
+	{Name: "hmac-sha1", Alias: "sha1", Size: 20, Hash: sha1.New, Approved: true},
+	{Name: "hmac-sha224", Alias: "sha224", Size: 28, Hash: sha256.New224, Approved: true},
+	{Name: "hmac-sha384", Alias: "sha384", Size: 48, Hash: sha512.New384, Approved: true},
+	{Name: "hmac-sha3-256", Alias: "sha3-256", Size: 32, Hash: sha3.New256, Approved: true},
+	{Name: "hmac-sha3-512", Alias: "sha3-512", Size: 64, Hash: sha3.New512, Approved: true},
+	{Name: "hmac-blake2b-512", Alias: "blake2b", Size: 64, Hash: blake2bNew(64)},
+	{Name: "keyed-blake2b-256", Size: 32, Hash: blake2bNew(32), Keyed: blake2bKeyed(32)},
+	{Name: "keyed-blake2b-512", Size: 64, Hash: blake2bNew(64), Keyed: blake2bKeyed(64)},
+	{Name: "hmac-streebog256", Size: 32, Hash: NewStreebog256},
+	{Name: "hmac-streebog512", Size: 64, Hash: NewStreebog512},
+}
+
+func blake2bNew(size int) func() hash.Hash {
+	return func() hash.Hash {
+		h, _ := blake2b.New(size, nil)
+		return h
+	}
+}
+
+func blake2bKeyed(size int) func(key []byte) (hash.Hash, error) {
+	return func(key []byte) (hash.Hash, error) {
+		if len(key) > blake2b.Size {
+			return nil, fmt.Errorf("keyed BLAKE2b takes passwords of at most %d bytes, got %d; pre-hash longer ones", blake2b.Size, len(key))
+		}
+		return blake2b.New(size, key)
+	}
+}
+
+// LookupPRF finds a PRF by canonical name or legacy alias.
+func LookupPRF(name string) (PRF, bool) {
+	for _, p := range PRFs {
+		if name == p.Name || (p.Alias != "" && name == p.Alias) {
+			return p, true
+		}
+	}
+	return PRF{}, false
+}
+
+// PRFNames lists the canonical names of PRFs.
+func PRFNames() []string {
+	names := make([]string, 0, len(PRFs))
+	for _, p := range PRFs {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Key runs PBKDF2 with p as defined by RFC 8018, which is HMAC over p.Hash unless p has a native keyed mode.
+func Key(p PRF, password, salt []byte, iterations, keyLen int) ([]byte, error) {
+	if p.Keyed == nil {
+		return pbkdf2.Key(password, salt, iterations, keyLen, p.Hash), nil
+	}
+
+	h, err := p.Keyed(password)
+	if err != nil {
+		return nil, err
+	}
+	dk := make([]byte, 0, keyLen+p.Size)
+	u := make([]byte, 0, p.Size)
+	for block := uint32(1); len(dk) < keyLen; block++ {
+		h.Reset()
+		h.Write(salt)
+		h.Write(binary.BigEndian.AppendUint32(nil, block))
+		u = h.Sum(u[:0])
+		t := append([]byte(nil), u...)
+		for n := 1; n < iterations; n++ {
+			h.Reset()
+			h.Write(u)
+			u = h.Sum(u[:0])
+			for i := range t {
+				t[i] ^= u[i]
+			}
+		}
+		dk = append(dk, t...)
+	}
+	return dk[:keyLen], nil
+}
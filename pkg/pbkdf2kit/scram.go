@@ -0,0 +1,66 @@
+package pbkdf2kit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SCRAMVerifier holds the parts of a stored SCRAM-SHA-256 verifier.
+type SCRAMVerifier struct {
+	Iterations int
+	Salt       []byte
+	StoredKey  []byte
+	ServerKey  []byte
+}
+
+// SCRAMKeys derives the RFC 5802 StoredKey and ServerKey from a salted password.
+func SCRAMKeys(saltedPassword []byte) ([]byte, []byte) {
+	clientKey := hmac.New(sha256.New, saltedPassword)
+	clientKey.Write([]byte("Client Key"))
+	storedKey := sha256.Sum256(clientKey.Sum(nil))
+	serverKey := hmac.New(sha256.New, saltedPassword)
+	serverKey.Write([]byte("Server Key"))
+	return storedKey[:], serverKey.Sum(nil)
+}
+
+// ParseSCRAMVerifier splits `SCRAM-SHA-256$<iterations>:<b64 salt>$<b64 StoredKey>:<b64 ServerKey>`.
+// Like ParseHash, errors never quote the input.
+func ParseSCRAMVerifier(verifier string) (SCRAMVerifier, error) {
+	var parsed SCRAMVerifier
+
+	rest, ok := strings.CutPrefix(verifier, "SCRAM-SHA-256$")
+	if !ok {
+		return parsed, fmt.Errorf("unsupported scheme, expected SCRAM-SHA-256")
+	}
+	params, keys, ok := strings.Cut(rest, "$")
+	if !ok {
+		return parsed, fmt.Errorf("expected SCRAM-SHA-256$<iterations>:<salt>$<stored key>:<server key>")
+	}
+	iterations, salt, ok := strings.Cut(params, ":")
+	if !ok {
+		return parsed, fmt.Errorf("expected <iterations>:<salt>")
+	}
+	storedKey, serverKey, ok := strings.Cut(keys, ":")
+	if !ok {
+		return parsed, fmt.Errorf("expected <stored key>:<server key>")
+	}
+
+	var err error
+	if parsed.Iterations, err = strconv.Atoi(iterations); err != nil || parsed.Iterations < 1 {
+		return parsed, fmt.Errorf("iterations are not a positive number")
+	}
+	if parsed.Salt, err = base64.StdEncoding.DecodeString(salt); err != nil {
+		return parsed, fmt.Errorf("salt is not valid base64")
+	}
+	if parsed.StoredKey, err = base64.StdEncoding.DecodeString(storedKey); err != nil || len(parsed.StoredKey) != sha256.Size {
+		return parsed, fmt.Errorf("stored key is not a base64 encoded SHA-256 digest")
+	}
+	if parsed.ServerKey, err = base64.StdEncoding.DecodeString(serverKey); err != nil || len(parsed.ServerKey) != sha256.Size {
+		return parsed, fmt.Errorf("server key is not a base64 encoded SHA-256 digest")
+	}
+	return parsed, nil
+}
@@ -0,0 +1,36 @@
+package pbkdf2kit
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+func TestParseSCRAMVerifier(t *testing.T) {
+	const verifier = "SCRAM-SHA-256$4096:c2FsdC1mb3ItcGVuY2lsIQ==$qYIaB/m/tpnqMLDfPtE/qzjPOnnmhgn6gQCzOElTYqs=:hfb2Bd0V9bE3wFaapQhVZPBHXEUYhI3OanY5j3lTQbk="
+
+	parsed, err := ParseSCRAMVerifier(verifier)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Iterations != 4096 || string(parsed.Salt) != "salt-for-pencil!" {
+		t.Fatalf("got %d iterations and salt %q", parsed.Iterations, parsed.Salt)
+	}
+	storedKey, serverKey := SCRAMKeys(pbkdf2.Key([]byte("pencil"), parsed.Salt, parsed.Iterations, sha256.Size, sha256.New))
+	if !bytes.Equal(storedKey, parsed.StoredKey) || !bytes.Equal(serverKey, parsed.ServerKey) {
+		t.Error("keys derived from the password don't match the verifier")
+	}
+
+	for _, invalid := range []string{
+		"SCRAM-SHA-1$4096:c2FsdA==$a:b",
+		"SCRAM-SHA-256$4096:c2FsdA==",
+		"SCRAM-SHA-256$0:c2FsdA==$qYIaB/m/tpnqMLDfPtE/qzjPOnnmhgn6gQCzOElTYqs=:hfb2Bd0V9bE3wFaapQhVZPBHXEUYhI3OanY5j3lTQbk=",
+		"SCRAM-SHA-256$4096:c2FsdA==$c2hvcnQ=:hfb2Bd0V9bE3wFaapQhVZPBHXEUYhI3OanY5j3lTQbk=",
+	} {
+		if _, err := ParseSCRAMVerifier(invalid); err == nil {
+			t.Errorf("expected an error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package pbkdf2kit
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"hash"
+	"testing"
+)
+
+func TestStreebog(t *testing.T) {
+	// M1 of RFC 6986, section 10, whose byte order is reversed there, then a full block and a message of several blocks.
+	m1 := "012345678901234567890123456789012345678901234567890123456789012"
+	block := m1 + "3"
+	long := make([]byte, 200)
+	for i := range long {
+		long[i] = byte(i)
+	}
+	cases := []struct {
+		name     string
+		hash     func() hash.Hash
+		message  []byte
+		expected string
+	}{
+		{"512 M1", NewStreebog512, []byte(m1), "1b54d01a4af5b9d5cc3d86d68d285462b19abc2475222f35c085122be4ba1ffa00ad30f8767b3a82384c6574f024c311e2a481332b08ef7f41797891c1646f48"},
+		{"256 M1", NewStreebog256, []byte(m1), "9d151eefd8590b89daa6ba6cb74af9275dd051026bb149a452fd84e5e57b5500"},
+		{"512 block", NewStreebog512, []byte(block), "789d876832c7d0fef9b04acd3e558865dd6d64dc1c1000f2f7d342b7720a6062bb069cef4c17f0266d56ebbf12d29104065eec18666db2164f37cd61df46544f"},
+		{"256 block", NewStreebog256, []byte(block), "a976cb1524ea234e060d38c439ac83c2dc154f6d6adfd92365b8f88a29d8e666"},
+		{"512 long", NewStreebog512, long, "43946b2e8d58cb727df9affa1fffa19884aec42156f0933138aef821a9a8809ead7d39c061f85734f5e97b52e99d4813b71d04d2f39f838ae7a6bd256d03fa04"},
+		{"256 long", NewStreebog256, long, "c3c662d736c446b1e2937e9c4a13e4b0e1c6981cf267f46db2a163d86f716300"},
+	}
+	for _, c := range cases {
+		h := c.hash()
+		// Write in pieces, to cover blocks split across writes.
+		h.Write(c.message[:5])
+		h.Write(c.message[5:])
+		if actual := hex.EncodeToString(h.Sum(nil)); actual != c.expected {
+			t.Errorf("%s: got %s, want %s", c.name, actual, c.expected)
+		}
+	}
+}
+
+func TestStreebogHMAC(t *testing.T) {
+	// The HMAC_GOSTR3411_2012_256 example of R 50.1.113-2016.
+	key, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	data, _ := hex.DecodeString("0126bdb87800af214341456563780100")
+	mac := hmac.New(NewStreebog256, key)
+	mac.Write(data)
+	if actual := hex.EncodeToString(mac.Sum(nil)); actual != "a1aa5f7de402d7b3d323f2991c8d4534013137010a83754fd0af6d7cd4922ed9" {
+		t.Errorf("got %s", actual)
+	}
+}
+
+func TestStreebogPBKDF2(t *testing.T) {
+	// The PBKDF2 examples of R 50.1.111-2016.
+	p, _ := LookupPRF("hmac-streebog512")
+	for iterations, expected := range map[int]string{
+		1:    "64770af7f748c3b1c9ac831dbcfd85c26111b30a8a657ddc3056b80ca73e040d2854fd36811f6d825cc4ab66ec0a68a490a9e5cf5156b3a2b7eecddbf9a16b47",
+		4096: "e52deb9a2d2aaff4e2ac9d47a41f34c20376591c67807f0477e32549dc341bc7867c09841b6d58e29d0347c996301d55df0d34e47cf68f4e3c2cdaf1d9ab86c3",
+	} {
+		dk, err := Key(p, []byte("password"), []byte("salt"), iterations, 64)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual := hex.EncodeToString(dk); actual != expected {
+			t.Errorf("%d iterations: got %s, want %s", iterations, actual, expected)
+		}
+	}
+}
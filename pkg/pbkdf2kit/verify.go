@@ -0,0 +1,62 @@
+package pbkdf2kit
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// Verify reports whether password derives to hash. It understands PostgreSQL SCRAM-SHA-256 verifiers, the layouts
+// of ParseHash, and the default `<b64 salt>:<b64 key>` result of pbkdf2_key, which doesn't record its parameters,
+// so iterations and prf apply to it. Errors never quote the hash, as a misplaced password could end up there.
+func Verify(hashValue, password string, iterations int, prfName string) (bool, error) {
+	return VerifyParams(hashValue, password, Params{PRF: prfName, Iterations: iterations})
+}
+
+// VerifyParams is Verify for hashes derived with a pepper, pre-hash or context. PreHash, Pepper and PepperMode apply
+// to every hash, while PRF, Iterations and Context only apply to the default `<b64 salt>:<b64 key>` result, as the
+// other layouts record their own PRF and iteration count and are never derived with a context.
+func VerifyParams(hashValue, password string, params Params) (bool, error) {
+	if strings.HasPrefix(hashValue, "SCRAM-SHA-256$") {
+		parsed, err := ParseSCRAMVerifier(hashValue)
+		if err != nil {
+			return false, err
+		}
+		params.PRF, params.Iterations, params.KeyLength, params.Context = "hmac-sha256", parsed.Iterations, sha256.Size, ""
+		dk, err := Derive(params, password, parsed.Salt)
+		if err != nil {
+			return false, err
+		}
+		storedKey, serverKey := SCRAMKeys(dk)
+		return subtle.ConstantTimeCompare(storedKey, parsed.StoredKey)&subtle.ConstantTimeCompare(serverKey, parsed.ServerKey) == 1, nil
+	}
+
+	if salt, key, err := ParseSaltKey(hashValue); err == nil {
+		if _, ok := LookupPRF(params.PRF); !ok {
+			return false, fmt.Errorf("unknown prf %q, expected one of %s", params.PRF, strings.Join(PRFNames(), ", "))
+		}
+		params.KeyLength = len(key)
+		dk, err := Derive(params, password, salt)
+		if err != nil {
+			return false, err
+		}
+		return subtle.ConstantTimeCompare(dk, key) == 1, nil
+	}
+
+	parsed, err := ParseHash(hashValue)
+	if err != nil {
+		return false, err
+	}
+	// The hashes of ParseHash are named by the bare hash, the alias of their HMAC PRF.
+	p, ok := LookupPRF(parsed.HashAlgorithm)
+	if !ok || p.Alias != parsed.HashAlgorithm {
+		return false, fmt.Errorf("unsupported hash %q", parsed.HashAlgorithm)
+	}
+	params.PRF, params.Iterations, params.KeyLength, params.Context = p.Name, parsed.Iterations, len(parsed.Key), ""
+	dk, err := Derive(params, password, parsed.Salt)
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(dk, parsed.Key) == 1, nil
+}
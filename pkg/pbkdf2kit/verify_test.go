@@ -0,0 +1,72 @@
+package pbkdf2kit
+
+import (
+	"encoding/base64"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	cases := []struct {
+		hash     string
+		password string
+		expected bool
+	}{
+		{"SCRAM-SHA-256$4096:c2FsdC1mb3ItcGVuY2lsIQ==$qYIaB/m/tpnqMLDfPtE/qzjPOnnmhgn6gQCzOElTYqs=:hfb2Bd0V9bE3wFaapQhVZPBHXEUYhI3OanY5j3lTQbk=", "pencil", true},
+		{"SCRAM-SHA-256$4096:c2FsdC1mb3ItcGVuY2lsIQ==$qYIaB/m/tpnqMLDfPtE/qzjPOnnmhgn6gQCzOElTYqs=:hfb2Bd0V9bE3wFaapQhVZPBHXEUYhI3OanY5j3lTQbk=", "pen", false},
+		{"pbkdf2_sha256$1000$salt$YywoEuRtRgQQK6dhjp1tfS+BKPYma0oDJk0qBGC33LM=", "password", true},
+		{"pbkdf2_sha1$1000$salt$boi+i61+rp2eEKoGEiQDT+1I0D8=", "password", true},
+		{"pbkdf2_sha3-256$1000$salt$7laptzEbsIHQu/qNw8J5jzCru+xjREJoKdlW7Qbq7Ks=", "password", true},
+		{"$pbkdf2$1000$c2FsdHNhbHQ$6f6/9Uv85mj94wGsyFVjzJ3HHvY", "password", true},
+		{"$pbkdf2$1000$c2FsdHNhbHQ$6f6/9Uv85mj94wGsyFVjzJ3HHvY", "Password", false},
+		{"c2FsdHNhbHRzYWx0c2FsdA==:T78tEi/mr8Yageny/jk6s5+Qanjd3ceXdjwOeEhX6bQ=", "password", true},
+	}
+
+	for _, c := range cases {
+		match, err := Verify(c.hash, c.password, 100000, "hmac-sha256")
+		if err != nil {
+			t.Errorf("%s: %s", c.hash, err)
+			continue
+		}
+		if match != c.expected {
+			t.Errorf("%s with %q: got %t, want %t", c.hash, c.password, match, c.expected)
+		}
+	}
+
+	// An empty key would match every password.
+	for _, invalid := range []string{"", "pbkdf2_md5$1000$salt$YQ==", "SCRAM-SHA-256$4096:c2FsdA==",
+		"c2FsdA==:", "$pbkdf2-sha256$1000$c2FsdA$", "pbkdf2_sha256$1000$salt$", "pbkdf2_sha256$0$salt$YQ=="} {
+		if _, err := Verify(invalid, "password", 100000, "hmac-sha256"); err == nil {
+			t.Errorf("%q: expected an error", invalid)
+		}
+	}
+}
+
+func TestVerifyParams(t *testing.T) {
+	params := Params{PRF: "hmac-sha256", Iterations: 1000, Pepper: "pepper", PepperMode: "hmac", PreHash: true, Context: "staging"}
+	salt := []byte("saltsaltsaltsalt")
+	key, err := Derive(params, "password", salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha256PRF, _ := LookupPRF("hmac-sha256")
+	plainKey, err := Derive(Params{PRF: "hmac-sha256", Iterations: 1000, Pepper: "pepper", PepperMode: "hmac", PreHash: true}, "password", salt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	passlib, _ := LookupPreset("passlib")
+	passlibHash := passlib.Format(sha256PRF, Material{Iterations: 1000, Salt: salt, Key: plainKey})
+
+	for hash, want := range map[string]bool{
+		base64.StdEncoding.EncodeToString(salt) + ":" + base64.StdEncoding.EncodeToString(key): true,
+		// Self-describing hashes are never derived with a context.
+		passlibHash: true,
+	} {
+		match, err := VerifyParams(hash, "password", params)
+		if err != nil || match != want {
+			t.Errorf("%s: got %t, %v", hash, match, err)
+		}
+		if match, _ := Verify(hash, "password", 1000, "hmac-sha256"); match {
+			t.Errorf("%s: matched without the pepper", hash)
+		}
+	}
+}